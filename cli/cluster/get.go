@@ -21,6 +21,7 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 )
 
@@ -51,6 +52,37 @@ func GetAPI(operatorConfig OperatorConfig, apiName string) (schema.GetAPIRespons
 	return apiRes, nil
 }
 
+func GetAutoscaling(operatorConfig OperatorConfig, apiName string, limit int) (schema.GetAutoscalingResponse, error) {
+	endpoint := path.Join("/autoscaling", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint, map[string]string{"limit": s.Int(limit)})
+	if err != nil {
+		return schema.GetAutoscalingResponse{}, err
+	}
+
+	var autoscalingRes schema.GetAutoscalingResponse
+	if err = json.Unmarshal(httpRes, &autoscalingRes); err != nil {
+		return schema.GetAutoscalingResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return autoscalingRes, nil
+}
+
+// the query params are the filters of the job history (e.g. status, label), and "after" to page through it
+func GetJobHistory(operatorConfig OperatorConfig, apiName string, qParams map[string]string) (schema.JobHistoryResponse, error) {
+	endpoint := path.Join("/history", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint, qParams)
+	if err != nil {
+		return schema.JobHistoryResponse{}, err
+	}
+
+	var jobHistoryRes schema.JobHistoryResponse
+	if err = json.Unmarshal(httpRes, &jobHistoryRes); err != nil {
+		return schema.JobHistoryResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return jobHistoryRes, nil
+}
+
 func GetJob(operatorConfig OperatorConfig, apiName string, jobID string) (schema.GetJobResponse, error) {
 	endpoint := path.Join("/batch", apiName, jobID)
 	httpRes, err := HTTPGet(operatorConfig, endpoint)
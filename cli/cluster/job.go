@@ -0,0 +1,138 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+func SubmitJob(operatorConfig OperatorConfig, apiName string, submission []byte) (spec.Job, error) {
+	endpoint := path.Join("/batch", apiName)
+	httpRes, err := HTTPPostJSON(operatorConfig, endpoint, submission)
+	if err != nil {
+		return spec.Job{}, err
+	}
+
+	var jobSpec spec.Job
+	if err = json.Unmarshal(httpRes, &jobSpec); err != nil {
+		return spec.Job{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return jobSpec, nil
+}
+
+func ListJobs(operatorConfig OperatorConfig, apiName string, limit int) (schema.ListJobsResponse, error) {
+	endpoint := path.Join("/batch", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint, map[string]string{"limit": s.Int(limit)})
+	if err != nil {
+		return schema.ListJobsResponse{}, err
+	}
+
+	var listJobsRes schema.ListJobsResponse
+	if err = json.Unmarshal(httpRes, &listJobsRes); err != nil {
+		return schema.ListJobsResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return listJobsRes, nil
+}
+
+func GetFailedBatches(operatorConfig OperatorConfig, apiName string, jobID string) (schema.GetFailedBatchesResponse, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "failed")
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetFailedBatchesResponse{}, err
+	}
+
+	var failedBatchesRes schema.GetFailedBatchesResponse
+	if err = json.Unmarshal(httpRes, &failedBatchesRes); err != nil {
+		return schema.GetFailedBatchesResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return failedBatchesRes, nil
+}
+
+func RetryFailedBatches(operatorConfig OperatorConfig, apiName string, jobID string) (spec.Job, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "failed", "retry")
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint)
+	if err != nil {
+		return spec.Job{}, err
+	}
+
+	var jobSpec spec.Job
+	if err = json.Unmarshal(httpRes, &jobSpec); err != nil {
+		return spec.Job{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return jobSpec, nil
+}
+
+func PauseJob(operatorConfig OperatorConfig, apiName string, jobID string) (schema.PauseJobResponse, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "pause")
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint)
+	if err != nil {
+		return schema.PauseJobResponse{}, err
+	}
+
+	var pauseRes schema.PauseJobResponse
+	if err = json.Unmarshal(httpRes, &pauseRes); err != nil {
+		return schema.PauseJobResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return pauseRes, nil
+}
+
+func ResumeJob(operatorConfig OperatorConfig, apiName string, jobID string, workers *int) (schema.ResumeJobResponse, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "resume")
+
+	qParams := map[string]string{}
+	if workers != nil {
+		qParams["workers"] = s.Int(*workers)
+	}
+
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint, qParams)
+	if err != nil {
+		return schema.ResumeJobResponse{}, err
+	}
+
+	var resumeRes schema.ResumeJobResponse
+	if err = json.Unmarshal(httpRes, &resumeRes); err != nil {
+		return schema.ResumeJobResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return resumeRes, nil
+}
+
+func ResizeJob(operatorConfig OperatorConfig, apiName string, jobID string, workers int) (schema.ResizeJobResponse, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "resize")
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint, map[string]string{"workers": s.Int(workers)})
+	if err != nil {
+		return schema.ResizeJobResponse{}, err
+	}
+
+	var resizeRes schema.ResizeJobResponse
+	if err = json.Unmarshal(httpRes, &resizeRes); err != nil {
+		return schema.ResizeJobResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return resizeRes, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func CreateJobSchedule(operatorConfig OperatorConfig, apiName string, jobSchedule []byte) (schema.CreateJobScheduleResponse, error) {
+	endpoint := path.Join("/schedules", apiName)
+	httpRes, err := HTTPPostJSON(operatorConfig, endpoint, jobSchedule)
+	if err != nil {
+		return schema.CreateJobScheduleResponse{}, err
+	}
+
+	var createRes schema.CreateJobScheduleResponse
+	if err = json.Unmarshal(httpRes, &createRes); err != nil {
+		return schema.CreateJobScheduleResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return createRes, nil
+}
+
+func GetJobSchedules(operatorConfig OperatorConfig, apiName string) (schema.GetJobSchedulesResponse, error) {
+	endpoint := path.Join("/schedules", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetJobSchedulesResponse{}, err
+	}
+
+	var schedulesRes schema.GetJobSchedulesResponse
+	if err = json.Unmarshal(httpRes, &schedulesRes); err != nil {
+		return schema.GetJobSchedulesResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return schedulesRes, nil
+}
+
+func DeleteJobSchedule(operatorConfig OperatorConfig, apiName string, scheduleName string) (schema.DeleteResponse, error) {
+	endpoint := path.Join("/schedules", apiName, scheduleName)
+	httpRes, err := HTTPDelete(operatorConfig, endpoint)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	var deleteRes schema.DeleteResponse
+	if err = json.Unmarshal(httpRes, &deleteRes); err != nil {
+		return schema.DeleteResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return deleteRes, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func CreateEndpointToken(operatorConfig OperatorConfig, apiName string, description string) (schema.CreateEndpointTokenResponse, error) {
+	endpoint := path.Join("/tokens", apiName)
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint, map[string]string{"description": description})
+	if err != nil {
+		return schema.CreateEndpointTokenResponse{}, err
+	}
+
+	var tokenRes schema.CreateEndpointTokenResponse
+	if err = json.Unmarshal(httpRes, &tokenRes); err != nil {
+		return schema.CreateEndpointTokenResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return tokenRes, nil
+}
+
+func GetEndpointTokens(operatorConfig OperatorConfig, apiName string) (schema.GetEndpointTokensResponse, error) {
+	endpoint := path.Join("/tokens", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetEndpointTokensResponse{}, err
+	}
+
+	var tokensRes schema.GetEndpointTokensResponse
+	if err = json.Unmarshal(httpRes, &tokensRes); err != nil {
+		return schema.GetEndpointTokensResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return tokensRes, nil
+}
+
+func DeleteEndpointToken(operatorConfig OperatorConfig, apiName string, tokenID string) (schema.DeleteResponse, error) {
+	endpoint := path.Join("/tokens", apiName, tokenID)
+	httpRes, err := HTTPDelete(operatorConfig, endpoint)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	var deleteRes schema.DeleteResponse
+	if err = json.Unmarshal(httpRes, &deleteRes); err != nil {
+		return schema.DeleteResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return deleteRes, nil
+}
@@ -26,8 +26,10 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
@@ -70,6 +72,18 @@ const (
 	ErrShellCompletionNotSupported          = "cli.shell_completion_not_supported"
 	ErrNoTerminalWidth                      = "cli.no_terminal_width"
 	ErrDeployFromTopLevelDir                = "cli.deploy_from_top_level_dir"
+	ErrFlagRequiresAPIName                  = "cli.flag_requires_api_name"
+	ErrUnsupportedSeriesFormat              = "cli.unsupported_series_format"
+	ErrRealtimeAPINameRequired              = "cli.realtime_api_name_required"
+	ErrRealtimeAPINotFoundInConfig          = "cli.realtime_api_not_found_in_config"
+	ErrJobSubmissionRequired                = "cli.job_submission_required"
+	ErrJobBatchSizeWithoutItems             = "cli.job_batch_size_without_items"
+	ErrInvalidJobItemsFile                  = "cli.invalid_job_items_file"
+	ErrJobDidNotSucceed                     = "cli.job_did_not_succeed"
+	ErrJobScheduleNotFound                  = "cli.job_schedule_not_found"
+	ErrInvalidUpstreamJob                   = "cli.invalid_upstream_job"
+	ErrInvalidJobLabel                      = "cli.invalid_job_label"
+	ErrInvalidJobWorkers                    = "cli.invalid_job_workers"
 )
 
 func ErrorInvalidProvider(providerStr string) error {
@@ -297,3 +311,94 @@ func ErrorDeployFromTopLevelDir(genericDirName string, providerType types.Provid
 		Message: fmt.Sprintf("cannot deploy from your %s directory - when deploying your API, cortex sends all files in your project directory (i.e. the directory which contains cortex.yaml) to your %s (see https://docs.cortex.dev/v/%s/deployments/realtime-api/predictors#project-files for Realtime API and https://docs.cortex.dev/v/%s/deployments/batch-api/predictors#project-files for Batch API); therefore it is recommended to create a subdirectory for your project files", genericDirName, targetStr, consts.CortexVersionMinor, consts.CortexVersionMinor),
 	})
 }
+
+func ErrorFlagRequiresAPIName(flag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFlagRequiresAPIName,
+		Message: fmt.Sprintf("the `%s` flag can only be used when getting a single api (e.g. `cortex get API_NAME %s`)", flag, flag),
+	})
+}
+
+func ErrorUnsupportedSeriesFormat(seriesPath string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnsupportedSeriesFormat,
+		Message: fmt.Sprintf("%s: unsupported file format (only .csv and .json files are supported)", seriesPath),
+	})
+}
+
+func ErrorRealtimeAPINameRequired(configFileName string, apiNames []string) error {
+	if len(apiNames) == 0 {
+		return errors.WithStack(&errors.Error{
+			Kind:    ErrRealtimeAPINameRequired,
+			Message: fmt.Sprintf("%s does not contain any realtime apis", configFileName),
+		})
+	}
+
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRealtimeAPINameRequired,
+		Message: fmt.Sprintf("%s contains multiple realtime apis (%s); please specify which one to use with the `--api` flag", configFileName, s.StrsAnd(apiNames)),
+	})
+}
+
+func ErrorRealtimeAPINotFoundInConfig(configFileName string, apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRealtimeAPINotFoundInConfig,
+		Message: fmt.Sprintf("%s does not contain a realtime api named %s", configFileName, apiName),
+	})
+}
+
+func ErrorJobSubmissionRequired() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobSubmissionRequired,
+		Message: "please provide a submission file, or the items to submit with the `--items` flag",
+	})
+}
+
+func ErrorJobBatchSizeWithoutItems() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobBatchSizeWithoutItems,
+		Message: fmt.Sprintf("the `--batch-size` flag can only be used if the submission file contains %s, or if the `--items` flag is specified", s.StrsOr([]string{schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey, schema.CSVFilesKey})),
+	})
+}
+
+func ErrorInvalidJobItemsFile(path string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobItemsFile,
+		Message: fmt.Sprintf("%s: unable to read items (the file must contain either a json list, or newline delimited json): %s", path, errors.Message(err)),
+	})
+}
+
+func ErrorJobDidNotSucceed(jobID string, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobDidNotSucceed,
+		Message: fmt.Sprintf("job %s did not succeed (status: %s)", jobID, jobStatus.Message()),
+	})
+}
+
+func ErrorJobScheduleNotFound(apiName string, scheduleName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobScheduleNotFound,
+		Message: fmt.Sprintf("unable to find job schedule %s for api %s (run `cortex schedule list %s` to list its schedules)", scheduleName, apiName, apiName),
+	})
+}
+
+func ErrorInvalidUpstreamJob(upstreamJob string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidUpstreamJob,
+		Message: fmt.Sprintf("invalid upstream job \"%s\" (expected API_NAME/JOB_ID)", upstreamJob),
+	})
+}
+
+func ErrorInvalidJobLabel(label string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobLabel,
+		Message: fmt.Sprintf("invalid label \"%s\" (expected KEY=VALUE)", label),
+	})
+}
+
+func ErrorInvalidJobWorkers(workers string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobWorkers,
+		Message: fmt.Sprintf("invalid number of workers \"%s\" (expected a positive integer)", workers),
+	})
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
@@ -51,14 +52,30 @@ const (
 )
 
 var (
-	_flagGetEnv string
-	_flagWatch  bool
+	_flagGetEnv             string
+	_flagWatch              bool
+	_flagGetAutoscaling     bool
+	_flagGetLimit           int
+	_flagGetJobs            bool
+	_flagGetJobStatus       string
+	_flagGetSubmittedAfter  string
+	_flagGetSubmittedBefore string
+	_flagGetJobLabel        string
+	_flagGetJobsPage        string
 )
 
 func getInit() {
 	_getCmd.Flags().SortFlags = false
 	_getCmd.Flags().StringVarP(&_flagGetEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
 	_getCmd.Flags().BoolVarP(&_flagWatch, "watch", "w", false, "re-run the command every 2 seconds")
+	_getCmd.Flags().BoolVar(&_flagGetAutoscaling, "autoscaling", false, "show the autoscaler's recent decisions for a realtime api")
+	_getCmd.Flags().IntVar(&_flagGetLimit, "limit", 20, "the number of autoscaler decisions or jobs to show (used with --autoscaling or --jobs)")
+	_getCmd.Flags().BoolVar(&_flagGetJobs, "jobs", false, "search the job history of a batch api")
+	_getCmd.Flags().StringVar(&_flagGetJobStatus, "status", "", "only show jobs with this status, e.g. succeeded or worker_error (used with --jobs)")
+	_getCmd.Flags().StringVar(&_flagGetSubmittedAfter, "submitted-after", "", "only show jobs submitted after this time, as a date (2006-01-02) or an RFC 3339 timestamp (used with --jobs)")
+	_getCmd.Flags().StringVar(&_flagGetSubmittedBefore, "submitted-before", "", "only show jobs submitted before this time, as a date (2006-01-02) or an RFC 3339 timestamp (used with --jobs)")
+	_getCmd.Flags().StringVar(&_flagGetJobLabel, "label", "", "only show jobs with this label, as KEY or KEY=VALUE (used with --jobs)")
+	_getCmd.Flags().StringVar(&_flagGetJobsPage, "page", "", "show the jobs submitted before this job id (used with --jobs to show the next page)")
 }
 
 var _getCmd = &cobra.Command{
@@ -78,6 +95,13 @@ var _getCmd = &cobra.Command{
 			telemetry.Event("cli.get")
 		}
 
+		if _flagGetAutoscaling && len(args) != 1 {
+			exit.Error(ErrorFlagRequiresAPIName("--autoscaling"))
+		}
+		if _flagGetJobs && len(args) != 1 {
+			exit.Error(ErrorFlagRequiresAPIName("--jobs"))
+		}
+
 		rerun(func() (string, error) {
 			if len(args) == 1 {
 				env, err := ReadOrConfigureEnv(_flagGetEnv)
@@ -89,6 +113,31 @@ var _getCmd = &cobra.Command{
 				if err != nil {
 					return "", err
 				}
+
+				if _flagGetAutoscaling {
+					if env.Provider == types.LocalProviderType {
+						return "", errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get autoscaling decisions for api %s", args[0]))
+					}
+
+					autoscalingTable, err := getAutoscaling(env, args[0])
+					if err != nil {
+						return "", err
+					}
+					return out + autoscalingTable, nil
+				}
+
+				if _flagGetJobs {
+					if env.Provider == types.LocalProviderType {
+						return "", errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get the job history of api %s", args[0]))
+					}
+
+					jobsTable, err := getJobHistory(env, args[0])
+					if err != nil {
+						return "", err
+					}
+					return out + jobsTable, nil
+				}
+
 				apiTable, err := getAPI(env, args[0])
 				if err != nil {
 					return "", err
@@ -400,6 +449,58 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 	return realtimeAPITable(apiRes.RealtimeAPI, env)
 }
 
+func getAutoscaling(env cliconfig.Environment, apiName string) (string, error) {
+	autoscalingRes, err := cluster.GetAutoscaling(MustGetOperatorConfig(env.Name), apiName, _flagGetLimit)
+	if err != nil {
+		// note: if modifying this string, search the codebase for it and change all occurrences
+		if strings.HasSuffix(errors.Message(err), "is not deployed") {
+			return console.Bold(errors.Message(err)), nil
+		}
+		return "", err
+	}
+
+	return autoscalingDecisionsTable(autoscalingRes), nil
+}
+
+func getJobHistory(env cliconfig.Environment, apiName string) (string, error) {
+	qParams := map[string]string{
+		"limit":  s.Int(_flagGetLimit),
+		"status": _flagGetJobStatus,
+		"label":  _flagGetJobLabel,
+		"after":  _flagGetJobsPage,
+	}
+
+	for param, timeStr := range map[string]string{"submitted_after": _flagGetSubmittedAfter, "submitted_before": _flagGetSubmittedBefore} {
+		// dates are converted to timestamps at midnight utc
+		if date, err := time.Parse("2006-01-02", timeStr); err == nil {
+			timeStr = date.Format(time.RFC3339)
+		}
+		qParams[param] = timeStr
+	}
+
+	jobHistoryRes, err := cluster.GetJobHistory(MustGetOperatorConfig(env.Name), apiName, qParams)
+	if err != nil {
+		// note: if modifying this string, search the codebase for it and change all occurrences
+		if strings.HasSuffix(errors.Message(err), "is not deployed") {
+			return console.Bold(errors.Message(err)), nil
+		}
+		return "", err
+	}
+
+	if len(jobHistoryRes.JobStatuses) == 0 {
+		if jobHistoryRes.NextPage != "" {
+			return console.Bold("no matching jobs were found among the most recent jobs") + "\n" + fmt.Sprintf("\nto keep searching, run the same command with `--page %s`\n", jobHistoryRes.NextPage), nil
+		}
+		return console.Bold("no matching jobs") + "\n", nil
+	}
+
+	out := jobStatusesTable(jobHistoryRes.JobStatuses)
+	if jobHistoryRes.NextPage != "" {
+		out += fmt.Sprintf("\nto show the next page, run the same command with `--page %s`\n", jobHistoryRes.NextPage)
+	}
+	return out, nil
+}
+
 func titleStr(title string) string {
 	return "\n" + console.Bold(title) + "\n"
 }
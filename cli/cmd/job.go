@@ -0,0 +1,493 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/spf13/cobra"
+)
+
+const _jobWaitInterval = 10 * time.Second
+
+var (
+	_flagJobEnv            string
+	_flagJobDownload       string
+	_flagJobWorkers        int
+	_flagJobBatchSize      int
+	_flagJobItems          []string
+	_flagJobDependsOn      []string
+	_flagJobLabels         []string
+	_flagJobIdempotencyKey string
+	_flagJobWait           bool
+	_flagJobLimit          int
+)
+
+func jobInit() {
+	_jobSubmitCmd.Flags().SortFlags = false
+	_jobSubmitCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobSubmitCmd.Flags().IntVarP(&_flagJobWorkers, "workers", "w", 0, "number of workers to allocate for the job (overrides workers in the submission file)")
+	_jobSubmitCmd.Flags().IntVarP(&_flagJobBatchSize, "batch-size", "b", 0, "number of items per batch (overrides batch_size in the submission file)")
+	_jobSubmitCmd.Flags().StringSliceVarP(&_flagJobItems, "items", "i", nil, "path of a file containing a json list or newline delimited json of items to submit in the request (can be specified multiple times)")
+	_jobSubmitCmd.Flags().StringSliceVar(&_flagJobDependsOn, "depends-on", nil, "an upstream job (API_NAME/JOB_ID) which must succeed before the job starts (can be specified multiple times)")
+	_jobSubmitCmd.Flags().StringArrayVarP(&_flagJobLabels, "label", "l", nil, "a label (KEY=VALUE) to add to the job (can be specified multiple times; overrides labels with the same key in the submission file)")
+	_jobSubmitCmd.Flags().StringVar(&_flagJobIdempotencyKey, "idempotency-key", "", "if a job was submitted with the same key within the last 24 hours, that job is returned instead of submitting a new one (overrides idempotency_key in the submission file)")
+	_jobSubmitCmd.Flags().BoolVar(&_flagJobWait, "wait", false, "wait for the job to complete (exits with a non-zero status if the job does not succeed)")
+	_jobCmd.AddCommand(_jobSubmitCmd)
+
+	_jobListCmd.Flags().SortFlags = false
+	_jobListCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobListCmd.Flags().IntVarP(&_flagJobLimit, "limit", "n", 20, "maximum number of jobs to list (jobs which are in progress are always listed)")
+	_jobCmd.AddCommand(_jobListCmd)
+
+	_jobWaitCmd.Flags().SortFlags = false
+	_jobWaitCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobCmd.AddCommand(_jobWaitCmd)
+
+	_jobFailedCmd.Flags().SortFlags = false
+	_jobFailedCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobFailedCmd.Flags().StringVarP(&_flagJobDownload, "download", "d", "", "path of a file to write the failed batches' items to (as newline delimited json)")
+	_jobCmd.AddCommand(_jobFailedCmd)
+
+	_jobRetryCmd.Flags().SortFlags = false
+	_jobRetryCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobCmd.AddCommand(_jobRetryCmd)
+
+	_jobPauseCmd.Flags().SortFlags = false
+	_jobPauseCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobCmd.AddCommand(_jobPauseCmd)
+
+	_jobResumeCmd.Flags().SortFlags = false
+	_jobResumeCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobResumeCmd.Flags().IntVarP(&_flagJobWorkers, "workers", "w", 0, "number of workers to resume the job with (defaults to the job's number of workers before it was paused)")
+	_jobCmd.AddCommand(_jobResumeCmd)
+
+	_jobResizeCmd.Flags().SortFlags = false
+	_jobResizeCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobCmd.AddCommand(_jobResizeCmd)
+}
+
+var _jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "manage batch jobs",
+}
+
+var _jobSubmitCmd = &cobra.Command{
+	Use:   "submit API_NAME [SUBMISSION_FILE]",
+	Short: "submit a job (the submission file contains the json request body of a job submission)",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.submit", cmd)
+
+		submissionPath := ""
+		if len(args) == 2 {
+			submissionPath = files.RelToAbsPath(args[1], _cwd)
+		}
+
+		submission, err := jobSubmission(submissionPath, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		jobSpec, err := cluster.SubmitJob(MustGetOperatorConfig(env.Name), args[0], submission)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("submitted job %s", jobSpec.ID))
+
+		if _flagJobWait {
+			fmt.Println()
+			waitForJob(env.Name, args[0], jobSpec.ID)
+			return
+		}
+
+		fmt.Printf("\nrun `cortex job wait %s %s` to wait for the job to complete, or `cortex get %s %s` to get its status\n", args[0], jobSpec.ID, args[0], jobSpec.ID)
+	},
+}
+
+var _jobListCmd = &cobra.Command{
+	Use:   "list API_NAME",
+	Short: "list the in progress and most recently submitted jobs of a batch api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.list", cmd)
+
+		listJobsRes, err := cluster.ListJobs(MustGetOperatorConfig(env.Name), args[0], _flagJobLimit)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(listJobsRes.JobStatuses) == 0 {
+			fmt.Println(console.Bold("no submitted jobs"))
+			return
+		}
+
+		fmt.Print(jobStatusesTable(listJobsRes.JobStatuses))
+	},
+}
+
+var _jobWaitCmd = &cobra.Command{
+	Use:   "wait API_NAME JOB_ID",
+	Short: "wait for a job to complete (exits with a non-zero status if the job does not succeed)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.wait", cmd)
+		waitForJob(env.Name, args[0], args[1])
+	},
+}
+
+var _jobFailedCmd = &cobra.Command{
+	Use:   "failed API_NAME JOB_ID",
+	Short: "list or download the batches which failed after exhausting their retries",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.failed", cmd)
+
+		failedBatchesRes, err := cluster.GetFailedBatches(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if failedBatchesRes.MaxRetries == nil {
+			fmt.Printf("failed batches are not kept for job %s because it was not submitted with %s\n", args[1], schema.MaxRetriesKey)
+			return
+		}
+
+		if len(failedBatchesRes.FailedBatches) == 0 {
+			fmt.Printf("job %s does not have any failed batches\n", args[1])
+			return
+		}
+
+		if _flagJobDownload != "" {
+			downloadPath := files.RelToAbsPath(_flagJobDownload, _cwd)
+			numItems, err := writeFailedBatchItems(failedBatchesRes.FailedBatches, downloadPath)
+			if err != nil {
+				exit.Error(err)
+			}
+			numBatches := len(failedBatchesRes.FailedBatches)
+			print.BoldFirstLine(fmt.Sprintf("wrote %d %s from %d failed %s to %s", numItems, s.PluralS("item", numItems), numBatches, s.PluralEs("batch", numBatches), downloadPath))
+			return
+		}
+
+		fmt.Print(failedBatchesTable(failedBatchesRes.FailedBatches))
+		fmt.Printf("\nrun `cortex job retry %s %s` to submit the failed batches as a new job\n", args[0], args[1])
+	},
+}
+
+var _jobRetryCmd = &cobra.Command{
+	Use:   "retry API_NAME JOB_ID",
+	Short: "submit the failed batches of a job as a new job",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.retry", cmd)
+
+		jobSpec, err := cluster.RetryFailedBatches(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("submitted job %s with the failed batches of job %s", jobSpec.ID, args[1]))
+	},
+}
+
+var _jobPauseCmd = &cobra.Command{
+	Use:   "pause API_NAME JOB_ID",
+	Short: "terminate the workers of a running job, keeping its remaining batches until it is resumed",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.pause", cmd)
+
+		pauseRes, err := cluster.PauseJob(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(pauseRes.Message)
+		fmt.Printf("\nrun `cortex job resume %s %s` to resume the job\n", args[0], args[1])
+	},
+}
+
+var _jobResumeCmd = &cobra.Command{
+	Use:   "resume API_NAME JOB_ID",
+	Short: "restore the workers of a paused job",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.resume", cmd)
+
+		var workers *int
+		if cmd.Flags().Changed("workers") {
+			workers = &_flagJobWorkers
+		}
+
+		resumeRes, err := cluster.ResumeJob(MustGetOperatorConfig(env.Name), args[0], args[1], workers)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(resumeRes.Message)
+	},
+}
+
+var _jobResizeCmd = &cobra.Command{
+	Use:   "resize API_NAME JOB_ID WORKERS",
+	Short: "change the number of workers of a running job",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagJobEnv, "cli.job.resize", cmd)
+
+		workers, ok := s.ParseInt(args[2])
+		if !ok || workers <= 0 {
+			exit.Error(ErrorInvalidJobWorkers(args[2]))
+		}
+
+		resizeRes, err := cluster.ResizeJob(MustGetOperatorConfig(env.Name), args[0], args[1], workers)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(resizeRes.Message)
+	},
+}
+
+func failedBatchesTable(failedBatches []schema.FailedBatch) string {
+	rows := make([][]interface{}, 0, len(failedBatches))
+	for _, failedBatch := range failedBatches {
+		numItems := "-"
+		var items []json.RawMessage
+		if err := json.Unmarshal(failedBatch.Payload, &items); err == nil {
+			numItems = s.Int(len(items))
+		}
+
+		rows = append(rows, []interface{}{
+			failedBatch.ID,
+			numItems,
+			s.Int64ToBase2Byte(int64(len(failedBatch.Payload))),
+		})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "batch id"},
+			{Title: "items"},
+			{Title: "size"},
+		},
+		Rows: rows,
+	}
+
+	return t.MustFormat()
+}
+
+// writes one item per line, so that the file can be resubmitted with delimited_files
+func writeFailedBatchItems(failedBatches []schema.FailedBatch, path string) (int, error) {
+	var buf bytes.Buffer
+	numItems := 0
+	for _, failedBatch := range failedBatches {
+		var items []json.RawMessage
+		if err := json.Unmarshal(failedBatch.Payload, &items); err != nil {
+			items = []json.RawMessage{failedBatch.Payload}
+		}
+
+		for _, item := range items {
+			if err := json.Compact(&buf, item); err != nil {
+				return 0, errors.Wrap(err, "batch "+failedBatch.ID)
+			}
+			buf.WriteString("\n")
+			numItems++
+		}
+	}
+
+	if err := files.WriteFile(buf.Bytes(), path); err != nil {
+		return 0, err
+	}
+
+	return numItems, nil
+}
+
+// builds the request body of a job submission from the submission file (if provided) and the submit command's flags
+func jobSubmission(submissionPath string, cmd *cobra.Command) ([]byte, error) {
+	submission := map[string]interface{}{}
+
+	if submissionPath != "" {
+		submissionBytes, err := files.ReadFileBytes(submissionPath)
+		if err != nil {
+			return nil, err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(submissionBytes))
+		decoder.UseNumber()
+		if err := decoder.Decode(&submission); err != nil {
+			return nil, errors.Wrap(err, submissionPath)
+		}
+	} else if len(_flagJobItems) == 0 {
+		return nil, ErrorJobSubmissionRequired()
+	}
+
+	if cmd.Flags().Changed("workers") {
+		submission[schema.WorkersKey] = _flagJobWorkers
+	}
+
+	if len(_flagJobItems) > 0 {
+		items := []json.RawMessage{}
+		for _, itemsPath := range _flagJobItems {
+			fileItems, err := readJobItems(files.RelToAbsPath(itemsPath, _cwd))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, fileItems...)
+		}
+
+		itemList, _ := submission[schema.ItemListKey].(map[string]interface{})
+		if itemList == nil {
+			itemList = map[string]interface{}{}
+		}
+		itemList[schema.ItemsKey] = items
+		submission[schema.ItemListKey] = itemList
+	}
+
+	if len(_flagJobDependsOn) > 0 {
+		dependsOn := make([]spec.JobKey, len(_flagJobDependsOn))
+		for i, upstreamJob := range _flagJobDependsOn {
+			parts := strings.Split(upstreamJob, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, ErrorInvalidUpstreamJob(upstreamJob)
+			}
+			dependsOn[i] = spec.JobKey{APIName: parts[0], ID: parts[1]}
+		}
+		submission[schema.DependsOnKey] = dependsOn
+	}
+
+	if len(_flagJobLabels) > 0 {
+		labels, _ := submission[schema.LabelsKey].(map[string]interface{})
+		if labels == nil {
+			labels = map[string]interface{}{}
+		}
+		for _, label := range _flagJobLabels {
+			parts := strings.SplitN(label, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return nil, ErrorInvalidJobLabel(label)
+			}
+			labels[parts[0]] = parts[1]
+		}
+		submission[schema.LabelsKey] = labels
+	}
+
+	if cmd.Flags().Changed("idempotency-key") {
+		submission[schema.IdempotencyKeyKey] = _flagJobIdempotencyKey
+	}
+
+	if cmd.Flags().Changed("batch-size") {
+		foundBatchSource := false
+		for _, key := range []string{schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey, schema.CSVFilesKey} {
+			if batchSource, ok := submission[key].(map[string]interface{}); ok {
+				batchSource[schema.BatchSizeKey] = _flagJobBatchSize
+				foundBatchSource = true
+			}
+		}
+		if !foundBatchSource {
+			return nil, ErrorJobBatchSizeWithoutItems()
+		}
+	}
+
+	return json.Marshal(submission)
+}
+
+// reads either a json list of items, or newline delimited json (one item per line)
+func readJobItems(path string) ([]json.RawMessage, error) {
+	itemsBytes, err := files.ReadFileBytes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if trimmed := bytes.TrimSpace(itemsBytes); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, ErrorInvalidJobItemsFile(path, err)
+		}
+		return items, nil
+	}
+
+	for i, line := range strings.Split(string(itemsBytes), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !json.Valid([]byte(line)) {
+			return nil, ErrorInvalidJobItemsFile(path, fmt.Errorf("line %d is not valid json", i+1))
+		}
+		items = append(items, json.RawMessage(line))
+	}
+
+	return items, nil
+}
+
+// polls the job's status until it is no longer in progress, and exits with a non-zero status if it did not succeed
+func waitForJob(envName string, apiName string, jobID string) {
+	operatorConfig := MustGetOperatorConfig(envName)
+
+	prevProgress := ""
+	for {
+		jobRes, err := cluster.GetJob(operatorConfig, apiName, jobID)
+		if err != nil {
+			exit.Error(err)
+		}
+		job := jobRes.JobStatus
+
+		succeeded, failed := 0, 0
+		if job.BatchMetrics != nil {
+			succeeded = job.BatchMetrics.Succeeded
+			failed = job.BatchMetrics.Failed
+		}
+
+		progress := fmt.Sprintf("%s (%d/%d %s succeeded", job.Status.Message(), succeeded, job.TotalBatchCount, s.PluralEs("batch", job.TotalBatchCount))
+		if failed > 0 {
+			progress += fmt.Sprintf(", %d failed", failed)
+		}
+		progress += ")"
+
+		if progress != prevProgress {
+			fmt.Printf("%s %s\n", time.Now().Format(_timeFormat), progress)
+			prevProgress = progress
+		}
+
+		if !job.Status.IsInProgress() {
+			if job.Status != status.JobSucceeded {
+				exit.Error(ErrorJobDidNotSucceed(jobID, job.Status))
+			}
+			fmt.Println()
+			print.BoldFirstLine(fmt.Sprintf("job %s succeeded", jobID))
+			return
+		}
+
+		time.Sleep(_jobWaitInterval)
+	}
+}
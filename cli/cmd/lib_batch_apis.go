@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
@@ -82,59 +84,87 @@ func batchAPIsTable(batchAPIs []schema.BatchAPI, envNames []string) table.Table
 }
 
 func batchAPITable(batchAPI schema.BatchAPI) string {
-	jobRows := make([][]interface{}, 0, len(batchAPI.JobStatuses))
-
 	out := ""
 	if len(batchAPI.JobStatuses) == 0 {
 		out = console.Bold("no submitted jobs\n")
 	} else {
-		totalFailed := 0
-		for _, job := range batchAPI.JobStatuses {
-			succeeded := 0
-			failed := 0
+		out += jobStatusesTable(batchAPI.JobStatuses)
+	}
 
-			if job.BatchMetrics != nil {
-				failed = job.BatchMetrics.Failed
-				succeeded = job.BatchMetrics.Succeeded
-				totalFailed += failed
-			}
+	out += "\n" + console.Bold("endpoint: ") + batchAPI.Endpoint
 
-			jobEndTime := time.Now()
-			if job.EndTime != nil {
-				jobEndTime = *job.EndTime
-			}
+	out += "\n" + titleStr("batch api configuration") + batchAPI.Spec.UserStr(types.AWSProviderType)
+	return out
+}
 
-			duration := jobEndTime.Sub(job.StartTime).Truncate(time.Second).String()
+func jobStatusesTable(jobStatuses []status.JobStatus) string {
+	jobRows := make([][]interface{}, 0, len(jobStatuses))
 
-			jobRows = append(jobRows, []interface{}{
-				job.ID,
-				job.Status.Message(),
-				fmt.Sprintf("%d/%d", succeeded, job.TotalBatchCount),
-				failed,
-				job.StartTime.Format(_timeFormat),
-				duration,
-			})
+	totalFailed := 0
+	hasLabels := false
+	for _, job := range jobStatuses {
+		succeeded := 0
+		failed := 0
+
+		if job.BatchMetrics != nil {
+			failed = job.BatchMetrics.Failed
+			succeeded = job.BatchMetrics.Succeeded
+			totalFailed += failed
 		}
 
-		t := table.Table{
-			Headers: []table.Header{
-				{Title: "job id"},
-				{Title: "status"},
-				{Title: "progress"}, // (succeeded/total)
-				{Title: "failed", Hidden: totalFailed == 0},
-				{Title: "start time"},
-				{Title: "duration"},
-			},
-			Rows: jobRows,
+		jobEndTime := time.Now()
+		if job.EndTime != nil {
+			jobEndTime = *job.EndTime
 		}
 
-		out += t.MustFormat()
+		duration := jobEndTime.Sub(job.StartTime).Truncate(time.Second).String()
+
+		labels := "-"
+		if len(job.Labels) > 0 {
+			labels = jobLabelsStr(job.Labels)
+			hasLabels = true
+		}
+
+		jobRows = append(jobRows, []interface{}{
+			job.ID,
+			job.Status.Message(),
+			fmt.Sprintf("%d/%d", succeeded, job.TotalBatchCount),
+			failed,
+			job.StartTime.Format(_timeFormat),
+			duration,
+			labels,
+		})
 	}
 
-	out += "\n" + console.Bold("endpoint: ") + batchAPI.Endpoint
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "job id"},
+			{Title: "status"},
+			{Title: "progress"}, // (succeeded/total)
+			{Title: "failed", Hidden: totalFailed == 0},
+			{Title: "start time"},
+			{Title: "duration"},
+			{Title: "labels", Hidden: !hasLabels},
+		},
+		Rows: jobRows,
+	}
 
-	out += "\n" + titleStr("batch api configuration") + batchAPI.Spec.UserStr(types.AWSProviderType)
-	return out
+	return t.MustFormat()
+}
+
+// e.g. "team=search, owner=data" (sorted by key)
+func jobLabelsStr(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	labelStrs := make([]string, len(keys))
+	for i, key := range keys {
+		labelStrs[i] = key + "=" + labels[key]
+	}
+	return strings.Join(labelStrs, ", ")
 }
 
 func getJob(env cliconfig.Environment, apiName string, jobID string) (string, error) {
@@ -150,6 +180,9 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 	jobIntroTable := table.KeyValuePairs{}
 	jobIntroTable.Add("job id", job.ID)
 	jobIntroTable.Add("status", job.Status.Message())
+	if len(job.Labels) > 0 {
+		jobIntroTable.Add("labels", jobLabelsStr(job.Labels))
+	}
 	out += jobIntroTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
 
 	jobTimingTable := table.KeyValuePairs{}
@@ -200,8 +233,16 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 
 	out += titleStr("batch stats") + t.MustFormat(&table.Opts{BoldHeader: pointer.Bool(false)})
 
-	if job.Status == status.JobEnqueuing {
+	if job.Progress != nil {
+		out += titleStr("progress") + jobProgressStr(*job.Progress)
+	}
+
+	if job.Status == status.JobWaiting {
+		out += "\nwaiting for upstream jobs to succeed, workers have not been allocated for this job yet\n"
+	} else if job.Status == status.JobEnqueuing {
 		out += "\nstill enqueuing, workers have not been allocated for this job yet\n"
+	} else if job.Status == status.JobPaused {
+		out += fmt.Sprintf("\nthis job is paused and its workers have been terminated (run `cortex job resume %s %s` to resume it)\n", apiName, jobID)
 	} else if job.Status.IsCompleted() {
 		out += "\nworker stats are not available because this job is not currently running\n"
 	} else {
@@ -238,6 +279,10 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 
 	out += "\n" + console.Bold("job endpoint: ") + resp.Endpoint + "\n"
 
+	if job.Output != nil {
+		out += console.Bold("output: ") + fmt.Sprintf("%s (%s format, %d %s written)", job.Output.S3Path, job.Output.Format, job.Output.ItemsWritten, s.PluralS("item", job.Output.ItemsWritten)) + "\n"
+	}
+
 	jobSpecStr, err := json.Pretty(job.Job)
 	if err != nil {
 		return "", err
@@ -247,3 +292,40 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 
 	return out, nil
 }
+
+func jobProgressStr(jobProgress status.JobProgress) string {
+	out := ""
+	if _flagWatch {
+		out += progressBar(jobProgress.PercentComplete) + "\n\n"
+	}
+
+	throughput := "-"
+	if jobProgress.BatchesPerSecond != nil {
+		throughput = fmt.Sprintf("%.2f batches/sec", *jobProgress.BatchesPerSecond)
+		if jobProgress.BatchesPerSecondPerWorker != nil {
+			throughput += fmt.Sprintf(" (%.2f per worker)", *jobProgress.BatchesPerSecondPerWorker)
+		}
+	}
+
+	timeRemaining := "-"
+	if jobProgress.EstimatedTimeRemaining != nil {
+		timeRemaining = (time.Duration(*jobProgress.EstimatedTimeRemaining) * time.Second).String()
+	}
+
+	progressTable := table.KeyValuePairs{}
+	progressTable.Add("completed", fmt.Sprintf("%.1f%%", jobProgress.PercentComplete))
+	progressTable.Add("throughput", throughput)
+	progressTable.Add("estimated time remaining", timeRemaining)
+	out += progressTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
+
+	return out
+}
+
+func progressBar(percentComplete float64) string {
+	width := 40
+	filled := int(percentComplete / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", width-filled) + "] " + fmt.Sprintf("%.1f%%", percentComplete)
+}
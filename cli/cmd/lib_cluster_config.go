@@ -547,6 +547,12 @@ func clusterConfigConfirmationStr(clusterConfig clusterconfig.Config, awsCreds A
 	if clusterConfig.APIGatewaySetting != defaultConfig.APIGatewaySetting {
 		items.Add(clusterconfig.APIGatewaySettingUserKey, clusterConfig.APIGatewaySetting)
 	}
+	if clusterConfig.AutoscalingMetricsSource != defaultConfig.AutoscalingMetricsSource {
+		items.Add(clusterconfig.AutoscalingMetricsSourceUserKey, clusterConfig.AutoscalingMetricsSource)
+	}
+	if clusterConfig.BatchJobRetentionDays != nil {
+		items.Add(clusterconfig.BatchJobRetentionDaysUserKey, *clusterConfig.BatchJobRetentionDays)
+	}
 
 	if clusterConfig.Spot != nil && *clusterConfig.Spot != *defaultConfig.Spot {
 		items.Add(clusterconfig.SpotUserKey, s.YesNo(clusterConfig.Spot != nil && *clusterConfig.Spot))
@@ -62,6 +62,10 @@ func realtimeAPITable(realtimeAPI *schema.RealtimeAPI, env cliconfig.Environment
 		}
 	}
 
+	if realtimeAPI.ActiveAutoscalingSchedule != nil {
+		out += "\n" + console.Bold("active autoscaling schedule: ") + activeAutoscalingScheduleStr(realtimeAPI.ActiveAutoscalingSchedule) + "\n"
+	}
+
 	if realtimeAPI.DashboardURL != "" {
 		out += "\n" + console.Bold("metrics dashboard: ") + realtimeAPI.DashboardURL + "\n"
 	}
@@ -79,6 +83,17 @@ func realtimeAPITable(realtimeAPI *schema.RealtimeAPI, env cliconfig.Environment
 	return out, nil
 }
 
+func activeAutoscalingScheduleStr(schedule *userconfig.AutoscalingSchedule) string {
+	str := fmt.Sprintf("\"%s\" for %s", schedule.Schedule, schedule.Duration.String())
+	if schedule.MinReplicas != nil {
+		str += fmt.Sprintf(", %s: %d", userconfig.MinReplicasKey, *schedule.MinReplicas)
+	}
+	if schedule.MaxReplicas != nil {
+		str += fmt.Sprintf(", %s: %d", userconfig.MaxReplicasKey, *schedule.MaxReplicas)
+	}
+	return str
+}
+
 func realtimeAPIsTable(realtimeAPIs []schema.RealtimeAPI, envNames []string) table.Table {
 	rows := make([][]interface{}, 0, len(realtimeAPIs))
 
@@ -132,6 +147,96 @@ func realtimeAPIsTable(realtimeAPIs []schema.RealtimeAPI, envNames []string) tab
 	}
 }
 
+func autoscalingDecisionsTable(autoscalingRes schema.GetAutoscalingResponse) string {
+	if len(autoscalingRes.Decisions) == 0 {
+		return console.Bold(fmt.Sprintf("the autoscaler has not made any decisions for %s yet", autoscalingRes.APIName)) + "\n"
+	}
+
+	rows := make([][]interface{}, 0, len(autoscalingRes.Decisions))
+
+	var hasPolicies bool
+	var hasSchedule bool
+	var hasPredictiveFloor bool
+	var hasMessage bool
+
+	for _, decision := range autoscalingRes.Decisions {
+		timeStr := decision.Timestamp.Local().Format("15:04:05")
+
+		if decision.Message != "" {
+			hasMessage = true
+			rows = append(rows, []interface{}{timeStr, "-", "-", "-", decision.CurrentReplicas, "-", "-", "-", "-", "-", "-", decision.Message})
+			continue
+		}
+
+		avgInFlightStr := "-"
+		if decision.AvgInFlight != nil {
+			avgInFlightStr = s.Round(*decision.AvgInFlight, 2, 0)
+		}
+
+		policyStrs := make([]string, 0, len(decision.Policies))
+		for _, policy := range decision.Policies {
+			policyStrs = append(policyStrs, fmt.Sprintf("%s: %s", policy.Policy, s.Round(policy.RawRecommendation, 2, 0)))
+		}
+		if len(policyStrs) > 0 {
+			hasPolicies = true
+		}
+
+		scheduleStr := "-"
+		if decision.ActiveSchedule != nil {
+			hasSchedule = true
+			scheduleStr = *decision.ActiveSchedule
+		}
+
+		if decision.PredictiveFloor != nil {
+			hasPredictiveFloor = true
+		}
+
+		stabilizationStr := fmt.Sprintf("%s - %s", int32PtrStr(decision.DownscaleStabilizationFloor), int32PtrStr(decision.UpscaleStabilizationCeil))
+
+		rows = append(rows, []interface{}{
+			timeStr,
+			avgInFlightStr,
+			s.Round(decision.RawRecommendation, 2, 0),
+			strings.Join(policyStrs, ", "),
+			decision.CurrentReplicas,
+			fmt.Sprintf("%d - %d", decision.MinReplicas, decision.MaxReplicas),
+			scheduleStr,
+			int32PtrStr(decision.PredictiveFloor),
+			decision.Recommendation,
+			stabilizationStr,
+			decision.Request,
+			"",
+		})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "time"},
+			{Title: "avg in-flight"},
+			{Title: "raw recommendation"},
+			{Title: "policies", Hidden: !hasPolicies},
+			{Title: "current"},
+			{Title: "bounds"},
+			{Title: "schedule", Hidden: !hasSchedule},
+			{Title: "predictive floor", Hidden: !hasPredictiveFloor},
+			{Title: "recommendation"},
+			{Title: "stabilization window"},
+			{Title: "request"},
+			{Title: "message", Hidden: !hasMessage},
+		},
+		Rows: rows,
+	}
+
+	return t.MustFormat()
+}
+
+func int32PtrStr(val *int32) string {
+	if val == nil {
+		return "-"
+	}
+	return s.Int32(*val)
+}
+
 func latencyStr(metrics *metrics.Metrics) string {
 	if metrics.NetworkStats == nil || metrics.NetworkStats.Latency == nil {
 		return "-"
@@ -22,10 +22,12 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -103,9 +105,13 @@ func init() {
 	deployInit()
 	envInit()
 	getInit()
+	jobInit()
 	logsInit()
 	predictInit()
 	refreshInit()
+	scheduleInit()
+	simulateInit()
+	tokenInit()
 	versionInit()
 }
 
@@ -140,6 +146,10 @@ func Execute() {
 	_rootCmd.AddCommand(_logsCmd)
 	_rootCmd.AddCommand(_predictCmd)
 	_rootCmd.AddCommand(_deleteCmd)
+	_rootCmd.AddCommand(_simulateCmd)
+	_rootCmd.AddCommand(_jobCmd)
+	_rootCmd.AddCommand(_scheduleCmd)
+	_rootCmd.AddCommand(_tokenCmd)
 
 	_rootCmd.AddCommand(_clusterCmd)
 	_rootCmd.AddCommand(_versionCmd)
@@ -197,6 +207,27 @@ func printEnvIfNotSpecified(envName string, cmd *cobra.Command) error {
 	return nil
 }
 
+// for commands which are only supported by clusters on aws; exits if the environment can't be read or is local
+func mustGetAWSEnv(envName string, eventName string, cmd *cobra.Command) cliconfig.Environment {
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+	err = printEnvIfNotSpecified(envName, cmd)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	if env.Provider == types.LocalProviderType {
+		exit.Error(ErrorNotSupportedInLocalEnvironment())
+	}
+
+	return env
+}
+
 func envStringIfNotSpecified(envName string, cmd *cobra.Command) (string, error) {
 	envNames, err := listConfiguredEnvNames()
 	if err != nil {
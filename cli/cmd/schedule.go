@@ -0,0 +1,222 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+var _flagScheduleEnv string
+
+func scheduleInit() {
+	_scheduleCreateCmd.Flags().SortFlags = false
+	_scheduleCreateCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_scheduleCmd.AddCommand(_scheduleCreateCmd)
+
+	_scheduleListCmd.Flags().SortFlags = false
+	_scheduleListCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_scheduleCmd.AddCommand(_scheduleListCmd)
+
+	_scheduleGetCmd.Flags().SortFlags = false
+	_scheduleGetCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_scheduleCmd.AddCommand(_scheduleGetCmd)
+
+	_scheduleDeleteCmd.Flags().SortFlags = false
+	_scheduleDeleteCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_scheduleCmd.AddCommand(_scheduleDeleteCmd)
+}
+
+var _scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "manage the schedules which submit jobs to a batch api",
+}
+
+var _scheduleCreateCmd = &cobra.Command{
+	Use:   "create API_NAME SCHEDULE_FILE",
+	Short: "create or update a job schedule (the schedule file contains the json definition of a job schedule)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagScheduleEnv, "cli.schedule.create", cmd)
+
+		jobScheduleBytes, err := files.ReadFileBytes(files.RelToAbsPath(args[1], _cwd))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		createRes, err := cluster.CreateJobSchedule(MustGetOperatorConfig(env.Name), args[0], jobScheduleBytes)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(createRes.Message)
+	},
+}
+
+var _scheduleListCmd = &cobra.Command{
+	Use:   "list API_NAME",
+	Short: "list the job schedules of a batch api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagScheduleEnv, "cli.schedule.list", cmd)
+
+		schedulesRes, err := cluster.GetJobSchedules(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(schedulesRes.Schedules) == 0 {
+			fmt.Printf("no job schedules have been created for %s (run `cortex schedule create %s SCHEDULE_FILE` to create one)\n", args[0], args[0])
+			return
+		}
+
+		rows := make([][]interface{}, 0, len(schedulesRes.Schedules))
+		for _, jobSchedule := range schedulesRes.Schedules {
+			lastRun := "-"
+			lastRunResult := "-"
+			if len(jobSchedule.Runs) > 0 {
+				lastRun = jobSchedule.Runs[0].ScheduledTime.Format(_timeFormat)
+				lastRunResult = jobScheduleRunResult(jobSchedule.Runs[0])
+			}
+
+			rows = append(rows, []interface{}{
+				jobSchedule.Name,
+				jobSchedule.Schedule,
+				jobSchedule.ConcurrencyPolicy,
+				lastRun,
+				lastRunResult,
+			})
+		}
+
+		t := table.Table{
+			Headers: []table.Header{
+				{Title: "name"},
+				{Title: "schedule"},
+				{Title: "concurrency policy"},
+				{Title: "last run"},
+				{Title: "result"},
+			},
+			Rows: rows,
+		}
+
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _scheduleGetCmd = &cobra.Command{
+	Use:   "get API_NAME SCHEDULE_NAME",
+	Short: "get a job schedule and its most recent runs",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagScheduleEnv, "cli.schedule.get", cmd)
+
+		schedulesRes, err := cluster.GetJobSchedules(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var jobSchedule *schema.JobScheduleStatus
+		for i := range schedulesRes.Schedules {
+			if schedulesRes.Schedules[i].Name == args[1] {
+				jobSchedule = &schedulesRes.Schedules[i]
+			}
+		}
+		if jobSchedule == nil {
+			exit.Error(ErrorJobScheduleNotFound(args[0], args[1]))
+		}
+
+		scheduleTable := table.KeyValuePairs{}
+		scheduleTable.Add("name", jobSchedule.Name)
+		scheduleTable.Add("schedule", jobSchedule.Schedule+" (utc)")
+		scheduleTable.Add("concurrency policy", jobSchedule.ConcurrencyPolicy)
+		scheduleTable.Add("created", jobSchedule.CreatedTime.Format(_timeFormat))
+		out := scheduleTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
+
+		if len(jobSchedule.Runs) == 0 {
+			out += "\n" + console.Bold("this schedule has not run yet") + "\n"
+		} else {
+			rows := make([][]interface{}, 0, len(jobSchedule.Runs))
+			for _, run := range jobSchedule.Runs {
+				jobID := run.JobID
+				if jobID == "" {
+					jobID = "-"
+				}
+				rows = append(rows, []interface{}{
+					run.ScheduledTime.Format(_timeFormat),
+					jobID,
+					jobScheduleRunResult(run),
+				})
+			}
+
+			t := table.Table{
+				Headers: []table.Header{
+					{Title: "scheduled time"},
+					{Title: "job id"},
+					{Title: "result"},
+				},
+				Rows: rows,
+			}
+			out += titleStr(fmt.Sprintf("most recent runs (up to %d)", jobSchedule.HistoryLimit)) + t.MustFormat()
+		}
+
+		submissionStr, err := json.Pretty(jobSchedule.Submission)
+		if err != nil {
+			exit.Error(err)
+		}
+		out += titleStr("job submission") + submissionStr
+
+		fmt.Print(out)
+	},
+}
+
+var _scheduleDeleteCmd = &cobra.Command{
+	Use:   "delete API_NAME SCHEDULE_NAME",
+	Short: "delete a job schedule (jobs which it submitted are not stopped)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagScheduleEnv, "cli.schedule.delete", cmd)
+
+		deleteRes, err := cluster.DeleteJobSchedule(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(deleteRes.Message)
+	},
+}
+
+func jobScheduleRunResult(run schema.JobScheduleRun) string {
+	switch {
+	case run.Skipped:
+		return "skipped (a previous job was still in progress)"
+	case run.Error != "":
+		return "failed to submit: " + run.Error
+	case run.Status != nil:
+		return run.Status.Message()
+	}
+	return "-"
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagSimulateAPI string
+)
+
+func simulateInit() {
+	_simulateCmd.Flags().SortFlags = false
+	_simulateCmd.Flags().StringVarP(&_flagSimulateAPI, "api", "a", "", "name of the realtime api whose autoscaling configuration to use (required if the configuration file contains multiple realtime apis)")
+}
+
+var _simulateCmd = &cobra.Command{
+	Use:   "simulate SERIES_FILE [CONFIG_FILE]",
+	Short: "replay a recorded series of in-flight requests through an api's autoscaling configuration",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.simulate")
+
+		seriesPath := files.RelToAbsPath(args[0], _cwd)
+		configPath := getConfigPath(args[1:])
+
+		samples, err := readSeries(seriesPath)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		api, err := readAPIForSimulation(configPath, _flagSimulateAPI)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		decisions := autoscaler.Simulate(api.Autoscaling, samples, spec.AutoscalingTickInterval)
+		fmt.Print(simulationStr(api, decisions))
+	},
+}
+
+func readSeries(seriesPath string) ([]autoscaler.Sample, error) {
+	seriesBytes, err := files.ReadFileBytes(seriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []autoscaler.Sample
+	switch strings.ToLower(filepath.Ext(seriesPath)) {
+	case ".csv":
+		samples, err = autoscaler.ParseCSVSeries(seriesBytes)
+	case ".json":
+		samples, err = autoscaler.ParseJSONSeries(seriesBytes)
+	default:
+		return nil, ErrorUnsupportedSeriesFormat(seriesPath)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, seriesPath)
+	}
+
+	return samples, nil
+}
+
+func readAPIForSimulation(configPath string, apiName string) (*userconfig.API, error) {
+	configFileName := filepath.Base(configPath)
+
+	configBytes, err := files.ReadFileBytes(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, types.AWSProviderType, configFileName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var realtimeAPIs []*userconfig.API
+	for i := range apiConfigs {
+		if apiConfigs[i].Kind == userconfig.RealtimeAPIKind {
+			realtimeAPIs = append(realtimeAPIs, &apiConfigs[i])
+		}
+	}
+
+	var api *userconfig.API
+	if apiName == "" {
+		if len(realtimeAPIs) != 1 {
+			apiNames := make([]string, len(realtimeAPIs))
+			for i, realtimeAPI := range realtimeAPIs {
+				apiNames[i] = realtimeAPI.Name
+			}
+			return nil, ErrorRealtimeAPINameRequired(configFileName, apiNames)
+		}
+		api = realtimeAPIs[0]
+	} else {
+		for _, realtimeAPI := range realtimeAPIs {
+			if realtimeAPI.Name == apiName {
+				api = realtimeAPI
+			}
+		}
+		if api == nil {
+			return nil, ErrorRealtimeAPINotFoundInConfig(configFileName, apiName)
+		}
+	}
+
+	if err := spec.ValidateAutoscaling(api); err != nil {
+		return nil, errors.Wrap(err, api.Identify())
+	}
+
+	return api, nil
+}
+
+func simulationStr(api *userconfig.API, decisions []schema.AutoscalerDecision) string {
+	tickInterval := spec.AutoscalingTickInterval
+	startTime := decisions[0].Timestamp
+	endTime := decisions[len(decisions)-1].Timestamp.Add(tickInterval)
+
+	var peakReplicas int32
+	var totalReplicaTicks int64
+	var numScalingEvents int
+	var numUnderProvisionedTicks int
+	var hasPredictiveFloor bool
+
+	rows := [][]interface{}{}
+	for i, decision := range decisions {
+		replicas := decision.Request
+
+		if replicas > peakReplicas {
+			peakReplicas = replicas
+		}
+		totalReplicaTicks += int64(replicas)
+
+		// the average number of in-flight requests per replica exceeded the target
+		if decision.AvgInFlight != nil && *decision.AvgInFlight > float64(decision.CurrentReplicas)*decision.TargetReplicaConcurrency {
+			numUnderProvisionedTicks++
+		}
+
+		if i > 0 && replicas == decision.CurrentReplicas {
+			continue
+		}
+		if i > 0 {
+			numScalingEvents++
+		}
+
+		avgInFlightStr := "-"
+		if decision.AvgInFlight != nil {
+			avgInFlightStr = s.Round(*decision.AvgInFlight, 2, 0)
+		}
+
+		if decision.PredictiveFloor != nil {
+			hasPredictiveFloor = true
+		}
+
+		rows = append(rows, []interface{}{
+			decision.Timestamp.Format("2006-01-02 15:04:05"),
+			avgInFlightStr,
+			int32PtrStr(decision.PredictiveFloor),
+			fmt.Sprintf("%d -> %d", decision.CurrentReplicas, replicas),
+		})
+	}
+
+	replicaHours := float64(totalReplicaTicks) * tickInterval.Hours()
+	underProvisionedPercent := 100 * float64(numUnderProvisionedTicks) / float64(len(decisions))
+
+	out := fmt.Sprintf("simulated %s of traffic (%d autoscaler ticks) for %s, starting at %s\n\n", endTime.Sub(startTime).String(), len(decisions), api.Name, startTime.Format(time.RFC3339))
+	out += fmt.Sprintf("%s %d\n", console.Bold("peak replicas:"), peakReplicas)
+	out += fmt.Sprintf("%s %s\n", console.Bold("replica hours:"), s.Round(replicaHours, 2, 0))
+	out += fmt.Sprintf("%s %d\n", console.Bold("scaling events:"), numScalingEvents)
+	out += fmt.Sprintf("%s %s%% of ticks (the average number of in-flight requests per replica exceeded %s: %s)\n", console.Bold("under-provisioned:"), s.Round(underProvisionedPercent, 1, 0), userconfig.TargetReplicaConcurrencyKey, s.Float64(*api.Autoscaling.TargetReplicaConcurrency))
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "time (utc)"},
+			{Title: "avg in-flight"},
+			{Title: "predictive floor", Hidden: !hasPredictiveFloor},
+			{Title: "replicas"},
+		},
+		Rows: rows,
+	}
+
+	out += titleStr("replicas") + t.MustFormat()
+
+	return out
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagTokenEnv         string
+	_flagTokenDescription string
+)
+
+func tokenInit() {
+	_tokenCreateCmd.Flags().SortFlags = false
+	_tokenCreateCmd.Flags().StringVarP(&_flagTokenEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_tokenCreateCmd.Flags().StringVarP(&_flagTokenDescription, "description", "d", "", "a description of the token (e.g. who it was created for)")
+	_tokenCmd.AddCommand(_tokenCreateCmd)
+
+	_tokenListCmd.Flags().SortFlags = false
+	_tokenListCmd.Flags().StringVarP(&_flagTokenEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_tokenCmd.AddCommand(_tokenListCmd)
+
+	_tokenDeleteCmd.Flags().SortFlags = false
+	_tokenDeleteCmd.Flags().StringVarP(&_flagTokenEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_tokenCmd.AddCommand(_tokenDeleteCmd)
+}
+
+var _tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "manage the tokens which authenticate requests to a batch api's endpoint",
+}
+
+var _tokenCreateCmd = &cobra.Command{
+	Use:   "create API_NAME",
+	Short: "create a token for a batch api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagTokenEnv, "cli.token.create", cmd)
+
+		tokenRes, err := cluster.CreateEndpointToken(MustGetOperatorConfig(env.Name), args[0], _flagTokenDescription)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("created token %s for %s", tokenRes.ID, args[0]))
+		fmt.Println("\n" + tokenRes.Token)
+		fmt.Println("\n" + console.Bold("this token will not be shown again") + "; include it in requests to the api's endpoint with the header \"Authorization: Bearer <token>\"")
+	},
+}
+
+var _tokenListCmd = &cobra.Command{
+	Use:   "list API_NAME",
+	Short: "list the tokens for a batch api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagTokenEnv, "cli.token.list", cmd)
+
+		tokensRes, err := cluster.GetEndpointTokens(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		out := console.Bold("endpoint auth: ") + tokensRes.EndpointAuth.String() + "\n\n"
+
+		if len(tokensRes.Tokens) == 0 {
+			out += fmt.Sprintf("no tokens have been created for %s (run `cortex token create %s` to create one)\n", args[0], args[0])
+			fmt.Print(out)
+			return
+		}
+
+		rows := make([][]interface{}, 0, len(tokensRes.Tokens))
+		for _, token := range tokensRes.Tokens {
+			description := token.Description
+			if description == "" {
+				description = "-"
+			}
+			rows = append(rows, []interface{}{
+				token.ID,
+				description,
+				token.CreatedTime.Format(_timeFormat),
+			})
+		}
+
+		t := table.Table{
+			Headers: []table.Header{
+				{Title: "token id"},
+				{Title: "description"},
+				{Title: "created"},
+			},
+			Rows: rows,
+		}
+
+		fmt.Print(out + t.MustFormat())
+	},
+}
+
+var _tokenDeleteCmd = &cobra.Command{
+	Use:   "delete API_NAME TOKEN_ID",
+	Short: "delete a batch api's token",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetAWSEnv(_flagTokenEnv, "cli.token.delete", cmd)
+
+		deleteRes, err := cluster.DeleteEndpointToken(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(deleteRes.Message)
+	},
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	_socketMode = "socket"
+	_fileMode   = "file"
+)
+
+var _modes = []string{_socketMode, _fileMode}
+
+const _socketRetryInterval = 5 * time.Second
+
+func modesStr() string {
+	return strings.Join(_modes, ", ")
+}
+
+// InFlightCounter tracks the number of in-flight requests on the replica
+type InFlightCounter interface {
+	// Sample is called every _requestSampleInterval, and returns the current number of in-flight requests
+	Sample() (int, error)
+	// Tick returns the stats since the previous tick
+	Tick(timestamp time.Time) *TickStats
+}
+
+func newInFlightCounter(mode string) (InFlightCounter, error) {
+	switch mode {
+	case _socketMode:
+		counter := newSocketCounter(socketPath)
+		go counter.Listen()
+		return counter, nil
+	case _fileMode:
+		return &fileCounter{dir: requestsDir}, nil
+	}
+
+	return nil, fmt.Errorf("invalid mode %q (must be one of %s)", mode, modesStr())
+}
+
+// fileCounter counts the files in a directory, which the API creates for each in-flight request
+type fileCounter struct {
+	dir     string
+	samples Counter
+}
+
+func (counter *fileCounter) Sample() (int, error) {
+	count, err := getFileCount(counter.dir)
+	if err != nil {
+		return 0, err
+	}
+	counter.samples.Append(count)
+	return count, nil
+}
+
+func (counter *fileCounter) Tick(timestamp time.Time) *TickStats {
+	return NewTickStats(counter.samples.GetAllAndDelete(), timestamp)
+}
+
+func getFileCount(dirPath string) (int, error) {
+	dir, err := os.Open(dirPath)
+	if err != nil {
+		return 0, err
+	}
+	defer dir.Close()
+	fileNames, err := dir.Readdirnames(0)
+	if err != nil {
+		return 0, err
+	}
+	return len(fileNames), nil
+}
+
+// socketCounter tracks the exact number of in-flight requests, which each of the API's processes reports over a unix socket
+// by writing a "start" line when it receives a request and an "end" line when it responds
+type socketCounter struct {
+	lock            sync.Mutex
+	socketPath      string
+	inFlight        int
+	peak            int       // the highest number of in-flight requests since the previous tick
+	inFlightSeconds float64   // the integral of the number of in-flight requests since the previous tick
+	numSamples      int       // the number of samples since the previous tick
+	lastChange      time.Time // the last time that inFlightSeconds was brought up to date
+	lastTick        time.Time
+}
+
+func newSocketCounter(socketPath string) *socketCounter {
+	now := time.Now()
+	return &socketCounter{
+		socketPath: socketPath,
+		lastChange: now,
+		lastTick:   now,
+	}
+}
+
+// must be called with the lock held
+func (counter *socketCounter) add(delta int, now time.Time) {
+	counter.inFlightSeconds += float64(counter.inFlight) * now.Sub(counter.lastChange).Seconds()
+	counter.lastChange = now
+
+	counter.inFlight += delta
+	if counter.inFlight > counter.peak {
+		counter.peak = counter.inFlight
+	}
+}
+
+func (counter *socketCounter) Sample() (int, error) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	counter.numSamples++
+	return counter.inFlight, nil
+}
+
+// The average is weighted by time rather than computed from the samples, so that short bursts between samples are accounted for
+func (counter *socketCounter) Tick(timestamp time.Time) *TickStats {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+
+	counter.add(0, timestamp)
+
+	stats := TickStats{
+		Timestamp:   timestamp,
+		MaxInFlight: counter.peak,
+		NumSamples:  counter.numSamples,
+	}
+	if elapsed := timestamp.Sub(counter.lastTick).Seconds(); elapsed > 0 {
+		stats.AvgInFlight = counter.inFlightSeconds / elapsed
+	}
+
+	counter.peak = counter.inFlight
+	counter.inFlightSeconds = 0
+	counter.numSamples = 0
+	counter.lastTick = timestamp
+
+	return &stats
+}
+
+// Listen accepts connections from the API's processes; errors are logged and retried, since the API can't be monitored without the socket
+func (counter *socketCounter) Listen() {
+	for {
+		if err := counter.listen(); err != nil {
+			log.Printf("error: listening on %s (retrying in %s): %s", counter.socketPath, _socketRetryInterval, err.Error())
+		}
+		time.Sleep(_socketRetryInterval)
+	}
+}
+
+func (counter *socketCounter) listen() error {
+	// the socket may have been left behind if the request monitor restarted
+	if err := os.Remove(counter.socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	listener, err := net.Listen("unix", counter.socketPath)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	// the API's processes may run as a different user
+	if err := os.Chmod(counter.socketPath, 0777); err != nil {
+		return err
+	}
+
+	log.Printf("listening for requests on %s", counter.socketPath)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Printf("error: accepting connection (retrying): %s", err.Error())
+				time.Sleep(_requestSampleInterval)
+				continue
+			}
+			return err
+		}
+		go counter.handleConn(conn)
+	}
+}
+
+func (counter *socketCounter) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	// the requests which are in flight on this connection, so that they can be discarded if the process exits before responding
+	connInFlight := 0
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		event := strings.TrimSpace(scanner.Text())
+
+		counter.lock.Lock()
+		switch event {
+		case "start":
+			connInFlight++
+			counter.add(1, time.Now())
+		case "end":
+			// an end event without a start event is possible if the process reconnected while the request was in flight
+			if connInFlight > 0 {
+				connInFlight--
+				counter.add(-1, time.Now())
+			}
+		default:
+			log.Printf("error: received an unexpected event: %q", event)
+		}
+		counter.lock.Unlock()
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Printf("error: reading from connection: %s", err.Error())
+	}
+
+	counter.lock.Lock()
+	counter.add(-connInFlight, time.Now())
+	counter.lock.Unlock()
+}
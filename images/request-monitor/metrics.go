@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metrics holds the replica's most recent sample and tick, and is served at /metrics in the Prometheus text format
+type Metrics struct {
+	lock      sync.Mutex
+	apiName   string
+	ready     bool
+	inFlight  *int
+	tickStats *TickStats
+}
+
+func (m *Metrics) SetInFlight(inFlight int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.inFlight = &inFlight
+}
+
+func (m *Metrics) SetTickStats(stats *TickStats) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.tickStats = stats
+}
+
+func (m *Metrics) SetReady(ready bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ready = ready
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	labels := fmt.Sprintf("{api_name=%s}", strconv.Quote(m.apiName))
+
+	var sb strings.Builder
+	writeGauge := func(name string, help string, value float64) {
+		sb.WriteString(fmt.Sprintf("# HELP %s %s\n", name, help))
+		sb.WriteString(fmt.Sprintf("# TYPE %s gauge\n", name))
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", name, labels, strconv.FormatFloat(value, 'f', -1, 64)))
+	}
+
+	readyValue := 0.0
+	if m.ready {
+		readyValue = 1
+	}
+	writeGauge("cortex_api_ready", "Whether the API on this replica is ready to receive requests (1) or not (0).", readyValue)
+
+	// the remaining metrics are omitted until they have been observed, so that they aren't mistaken for an idle replica
+	if m.inFlight != nil {
+		writeGauge("cortex_in_flight_requests", "The number of in-flight requests on this replica as of the most recent sample.", float64(*m.inFlight))
+	}
+
+	if m.tickStats != nil {
+		writeGauge("cortex_in_flight_requests_avg", fmt.Sprintf("The average number of in-flight requests on this replica during the most recent %s tick.", _tickInterval), m.tickStats.AvgInFlight)
+		writeGauge("cortex_in_flight_requests_max", fmt.Sprintf("The maximum number of in-flight requests on this replica during the most recent %s tick.", _tickInterval), float64(m.tickStats.MaxInFlight))
+		writeGauge("cortex_in_flight_requests_samples", fmt.Sprintf("The number of in-flight request samples which were taken during the most recent %s tick.", _tickInterval), float64(m.tickStats.NumSamples))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write([]byte(sb.String()))
+}
@@ -17,26 +17,31 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"sync"
 	"time"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cloudwatch"
 )
 
 const _tickOffset = 1 * time.Second
 const _tickInterval = 10 * time.Second
 const _requestSampleInterval = 1 * time.Second
 
+const _apiReadinessFile = "/mnt/workspace/api_readiness.txt"
+
 var (
-	client      *cloudwatch.CloudWatch
 	apiName     string
-	region      string
 	clusterName string
+	region      string
+	port        string
+	sinkType    string
+	mode        string
+	socketPath  string
+	requestsDir string
 )
 
 type Counter struct {
@@ -59,36 +64,106 @@ func (c *Counter) GetAllAndDelete() []int {
 	return output
 }
 
-// ./request-monitor api_name cluster_name
+// TickStats summarizes the in-flight request samples which were taken during one tick
+type TickStats struct {
+	Timestamp   time.Time `json:"timestamp"`
+	AvgInFlight float64   `json:"avg_in_flight"`
+	MaxInFlight int       `json:"max_in_flight"`
+	NumSamples  int       `json:"num_samples"`
+}
+
+func NewTickStats(requestCounts []int, timestamp time.Time) *TickStats {
+	stats := TickStats{
+		Timestamp:  timestamp,
+		NumSamples: len(requestCounts),
+	}
+
+	if len(requestCounts) == 0 {
+		return &stats
+	}
+
+	total := 0.0
+	for _, val := range requestCounts {
+		total += float64(val)
+		if val > stats.MaxInFlight {
+			stats.MaxInFlight = val
+		}
+	}
+	stats.AvgInFlight = total / float64(len(requestCounts))
+
+	return &stats
+}
+
+// InFlightStats holds the most recently published average, and is served to the operator when it scrapes the replica directly
+type InFlightStats struct {
+	lock      sync.Mutex
+	InFlight  *float64 `json:"in_flight"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+func (stats *InFlightStats) Set(inFlight float64, timestamp time.Time) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+	stats.InFlight = &inFlight
+	stats.Timestamp = timestamp.Unix()
+}
+
+func (stats *InFlightStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+var inFlightStats = InFlightStats{}
+
+var metrics = Metrics{}
+
+// ./request-monitor --api-name=API_NAME [--cluster-name=CLUSTER_NAME] [--region=REGION] [--port=PORT] [--sink=cloudwatch|prometheus|stdout] [--mode=socket|file]
 func main() {
-	apiName = os.Args[1]
-	clusterName = os.Args[2]
-	region = os.Getenv("CORTEX_REGION")
-
-	sess, err := session.NewSession(&aws.Config{
-		Credentials: nil,
-		Region:      aws.String(region),
-	})
+	flag.StringVar(&apiName, "api-name", "", "the name of the API (required)")
+	flag.StringVar(&clusterName, "cluster-name", "", "the name of the cluster, which is used as the CloudWatch namespace (required for the cloudwatch sink)")
+	flag.StringVar(&region, "region", os.Getenv("CORTEX_REGION"), "the AWS region (used by the cloudwatch sink)")
+	flag.StringVar(&port, "port", "", "if set, serve the most recent average at /in-flight and Prometheus metrics at /metrics on this port")
+	flag.StringVar(&sinkType, "sink", _cloudWatchSinkType, fmt.Sprintf("where to publish the in-flight request average every %s: %s", _tickInterval, sinkTypesStr()))
+	flag.StringVar(&mode, "mode", _fileMode, fmt.Sprintf("how in-flight requests are counted: %s", modesStr()))
+	flag.StringVar(&socketPath, "socket", "/mnt/request_monitor.sock", "the unix socket on which the API reports the start and end of each request (socket mode)")
+	flag.StringVar(&requestsDir, "requests-dir", "/mnt/requests", "the directory in which the API creates a file for each in-flight request (file mode)")
+	flag.Parse()
+
+	if apiName == "" {
+		log.Fatal("error: --api-name must be provided")
+	}
+	metrics.apiName = apiName
+
+	sink, err := newSink(sinkType)
 	if err != nil {
-		panic(err)
+		log.Fatalf("error: %s", err.Error())
 	}
 
-	client = cloudwatch.New(sess)
-	requestCounter := Counter{}
+	requestCounter, err := newInFlightCounter(mode)
+	if err != nil {
+		log.Fatalf("error: %s", err.Error())
+	}
+
+	if port != "" {
+		go serveStats(port)
+	}
 
 	os.OpenFile("/request_monitor_ready.txt", os.O_RDONLY|os.O_CREATE, 0666)
 
 	for {
-		if _, err := os.Stat("/mnt/workspace/api_readiness.txt"); err == nil {
+		if _, err := os.Stat(_apiReadinessFile); err == nil {
 			break
 		} else if os.IsNotExist(err) {
 			fmt.Println("waiting for replica to be ready ...")
 			time.Sleep(_tickInterval)
 		} else {
-			log.Printf("error encountered while looking for /mnt/workspace/api_readiness.txt") // unexpected
+			log.Printf("error encountered while looking for %s", _apiReadinessFile) // unexpected
 			time.Sleep(_tickInterval)
 		}
 	}
+	metrics.SetReady(true)
 
 	targetTime := time.Now()
 	roundedTime := targetTime.Round(_tickInterval)
@@ -106,78 +181,61 @@ func main() {
 	for {
 		select {
 		case <-startPublishing.C:
-			go startPublisher(apiName, &requestCounter, client)
+			go startPublisher(requestCounter, sink)
 		case <-requestSampler.C:
-			go updateOpenConnections(&requestCounter, requestSampler)
+			go updateOpenConnections(requestCounter, requestSampler)
 		}
 	}
 }
 
-func startPublisher(apiName string, requestCounter *Counter, client *cloudwatch.CloudWatch) {
+func startPublisher(requestCounter InFlightCounter, sink Sink) {
 	metricsPublisher := time.NewTicker(_tickInterval)
 	defer metricsPublisher.Stop()
 
-	go publishStats(apiName, requestCounter, client)
+	go publishStats(requestCounter, sink)
 	for {
 		select {
 		case <-metricsPublisher.C:
-			go publishStats(apiName, requestCounter, client)
+			go publishStats(requestCounter, sink)
 		}
 	}
 }
 
-func publishStats(apiName string, counter *Counter, client *cloudwatch.CloudWatch) {
-	requestCounts := counter.GetAllAndDelete()
+func publishStats(counter InFlightCounter, sink Sink) {
+	stats := counter.Tick(time.Now())
 
-	total := 0.0
-	if len(requestCounts) > 0 {
-		for _, val := range requestCounts {
-			total += float64(val)
-		}
+	log.Printf("recorded %.2f in-flight requests on replica", stats.AvgInFlight)
+	inFlightStats.Set(stats.AvgInFlight, stats.Timestamp)
+	metrics.SetTickStats(stats)
 
-		total /= float64(len(requestCounts))
-	}
-	log.Printf("recorded %.2f in-flight requests on replica", total)
-	curTime := time.Now()
-	metricData := cloudwatch.PutMetricDataInput{
-		Namespace: aws.String(clusterName),
-		MetricData: []*cloudwatch.MetricDatum{
-			{
-				MetricName: aws.String("in-flight"),
-				Dimensions: []*cloudwatch.Dimension{
-					{
-						Name:  aws.String("apiName"),
-						Value: aws.String(apiName),
-					},
-				},
-				Timestamp:         &curTime,
-				Value:             aws.Float64(total),
-				Unit:              aws.String("Count"),
-				StorageResolution: aws.Int64(1),
-			},
-		},
-	}
-	_, err := client.PutMetricData(&metricData)
-	if err != nil {
+	if err := sink.Publish(stats); err != nil {
 		log.Printf("error: publishing metrics: %s", err.Error())
 	}
 }
 
-func getFileCount() int {
-	dir, err := os.Open("/mnt/requests")
-	if err != nil {
-		panic(err)
-	}
-	defer dir.Close()
-	fileNames, err := dir.Readdirnames(0)
-	if err != nil {
-		panic(err)
+func serveStats(port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/in-flight", &inFlightStats)
+	mux.Handle("/metrics", &metrics)
+	for {
+		log.Printf("serving in-flight stats and metrics on port %s", port)
+		err := http.ListenAndServe(":"+port, mux)
+		log.Printf("error: serving in-flight stats and metrics (retrying in %s): %s", _tickInterval, err.Error())
+		time.Sleep(_tickInterval)
 	}
-	return len(fileNames)
 }
 
-func updateOpenConnections(requestCounter *Counter, timer *time.Timer) {
-	count := getFileCount()
-	requestCounter.Append(count)
-	timer.Reset(_requestSampleInterval)
+// errors are logged and the sample is skipped, so that a transient error doesn't stop the replica from being monitored
+func updateOpenConnections(requestCounter InFlightCounter, timer *time.Timer) {
+	defer timer.Reset(_requestSampleInterval)
+
+	_, err := os.Stat(_apiReadinessFile)
+	metrics.SetReady(err == nil)
+
+	count, err := requestCounter.Sample()
+	if err != nil {
+		log.Printf("error: counting in-flight requests: %s", err.Error())
+		return
+	}
+	metrics.SetInFlight(count)
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+const (
+	_cloudWatchSinkType = "cloudwatch"
+	_prometheusSinkType = "prometheus"
+	_stdoutSinkType     = "stdout"
+)
+
+var _sinkTypes = []string{_cloudWatchSinkType, _prometheusSinkType, _stdoutSinkType}
+
+func sinkTypesStr() string {
+	return strings.Join(_sinkTypes, ", ")
+}
+
+// Sink publishes the in-flight request stats at the end of each tick
+type Sink interface {
+	Publish(stats *TickStats) error
+}
+
+func newSink(sinkType string) (Sink, error) {
+	switch sinkType {
+	case _cloudWatchSinkType:
+		return newCloudWatchSink()
+	case _prometheusSinkType:
+		if port == "" {
+			return nil, fmt.Errorf("--port must be provided when using the %s sink", _prometheusSinkType)
+		}
+		return &prometheusSink{}, nil
+	case _stdoutSinkType:
+		return &stdoutSink{encoder: json.NewEncoder(os.Stdout)}, nil
+	}
+
+	return nil, fmt.Errorf("invalid sink %q (must be one of %s)", sinkType, sinkTypesStr())
+}
+
+// cloudWatchSink publishes the average to CloudWatch, where it is used for the API's dashboard and (by default) for autoscaling
+type cloudWatchSink struct {
+	client *cloudwatch.CloudWatch
+}
+
+func newCloudWatchSink() (*cloudWatchSink, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("--cluster-name must be provided when using the %s sink", _cloudWatchSinkType)
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: nil,
+		Region:      aws.String(region),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloudWatchSink{client: cloudwatch.New(sess)}, nil
+}
+
+func (sink *cloudWatchSink) Publish(stats *TickStats) error {
+	metricData := cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(clusterName),
+		MetricData: []*cloudwatch.MetricDatum{
+			{
+				MetricName: aws.String("in-flight"),
+				Dimensions: []*cloudwatch.Dimension{
+					{
+						Name:  aws.String("apiName"),
+						Value: aws.String(apiName),
+					},
+				},
+				Timestamp:         &stats.Timestamp,
+				Value:             aws.Float64(stats.AvgInFlight),
+				Unit:              aws.String("Count"),
+				StorageResolution: aws.Int64(1),
+			},
+		},
+	}
+
+	_, err := sink.client.PutMetricData(&metricData)
+	return err
+}
+
+// prometheusSink doesn't push anywhere, since the stats are scraped from /metrics
+type prometheusSink struct{}
+
+func (sink *prometheusSink) Publish(stats *TickStats) error {
+	return nil
+}
+
+// stdoutSink writes each tick's stats to stdout as a line of JSON
+type stdoutSink struct {
+	encoder *json.Encoder
+}
+
+func (sink *stdoutSink) Publish(stats *TickStats) error {
+	return sink.encoder.Encode(struct {
+		APIName string `json:"api_name"`
+		*TickStats
+	}{
+		APIName:   apiName,
+		TickStats: stats,
+	})
+}
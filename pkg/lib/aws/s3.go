@@ -612,6 +612,22 @@ func (c *Client) ListS3Prefix(bucket string, prefix string, includeDirObjects bo
 	return allObjects, nil
 }
 
+// Lists the objects whose keys are after startAfter (in ascending UTF-8 binary order)
+func (c *Client) ListS3PrefixStartAfter(bucket string, prefix string, startAfter string, includeDirObjects bool, maxResults *int64) ([]*s3.Object, error) {
+	var allObjects []*s3.Object
+
+	err := c.S3BatchIteratorStartAfter(bucket, prefix, startAfter, includeDirObjects, maxResults, func(objects []*s3.Object) (bool, error) {
+		allObjects = append(allObjects, objects...)
+		return true, nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, S3Path(bucket, prefix))
+	}
+
+	return allObjects, nil
+}
+
 func (c *Client) ListS3PathPrefix(s3Path string, includeDirObjects bool, maxResults *int64) ([]*s3.Object, error) {
 	bucket, prefix, err := SplitS3Path(s3Path)
 	if err != nil {
@@ -716,6 +732,11 @@ func (c *Client) S3Iterator(bucket string, prefix string, includeDirObjects bool
 // The return value of fn([]*s3.Object) (bool, error) should be whether to continue iterating, and an error (if any occurred)
 // Directory objects are empty objects ending in "/". They are not guaranteed to exists, and there may or may not be files "in" the directory
 func (c *Client) S3BatchIterator(bucket string, prefix string, includeDirObjects bool, maxResults *int64, fn func([]*s3.Object) (bool, error)) error {
+	return c.S3BatchIteratorStartAfter(bucket, prefix, "", includeDirObjects, maxResults, fn)
+}
+
+// Same as S3BatchIterator, but only iterates over the objects whose keys are after startAfter (if startAfter is empty, all objects are iterated over)
+func (c *Client) S3BatchIteratorStartAfter(bucket string, prefix string, startAfter string, includeDirObjects bool, maxResults *int64, fn func([]*s3.Object) (bool, error)) error {
 	var maxResultsRemaining *int64
 	if maxResults != nil {
 		maxResultsRemaining = pointer.Int64(*maxResults)
@@ -726,6 +747,9 @@ func (c *Client) S3BatchIterator(bucket string, prefix string, includeDirObjects
 		Prefix:  aws.String(prefix),
 		MaxKeys: maxResultsRemaining,
 	}
+	if startAfter != "" {
+		listObjectsInput.StartAfter = aws.String(startAfter)
+	}
 
 	var numSeen int64
 	var subErr error
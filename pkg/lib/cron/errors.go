@@ -0,0 +1,34 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	ErrInvalidSchedule = "cron.invalid_schedule"
+)
+
+func ErrorInvalidSchedule(expression string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSchedule,
+		Message: fmt.Sprintf("invalid cron schedule \"%s\": %s (expected 5 fields: minute, hour, day of month, month, and day of week, e.g. \"0 8 * * 1-5\")", expression, reason),
+	})
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a standard 5-field cron expression (minute, hour, day of month, month, day of week)
+type Schedule struct {
+	expression    string
+	minutes       uint64
+	hours         uint64
+	daysOfMonth   uint64
+	months        uint64
+	daysOfWeek    uint64
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type scheduleField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	_minuteField     = scheduleField{name: "minute", min: 0, max: 59}
+	_hourField       = scheduleField{name: "hour", min: 0, max: 23}
+	_dayOfMonthField = scheduleField{name: "day of month", min: 1, max: 31}
+	_monthField      = scheduleField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is an alias for sunday
+	_dayOfWeekField = scheduleField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var _scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func ParseSchedule(expression string) (*Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) == 1 {
+		if macro, ok := _scheduleMacros[strings.ToLower(fields[0])]; ok {
+			fields = strings.Fields(macro)
+		}
+	}
+
+	if len(fields) != 5 {
+		return nil, ErrorInvalidSchedule(expression, fmt.Sprintf("found %d fields", len(fields)))
+	}
+
+	schedule := Schedule{
+		expression:    expression,
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	var err error
+	if schedule.minutes, err = _minuteField.parse(fields[0]); err != nil {
+		return nil, ErrorInvalidSchedule(expression, err.Error())
+	}
+	if schedule.hours, err = _hourField.parse(fields[1]); err != nil {
+		return nil, ErrorInvalidSchedule(expression, err.Error())
+	}
+	if schedule.daysOfMonth, err = _dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, ErrorInvalidSchedule(expression, err.Error())
+	}
+	if schedule.months, err = _monthField.parse(fields[3]); err != nil {
+		return nil, ErrorInvalidSchedule(expression, err.Error())
+	}
+	if schedule.daysOfWeek, err = _dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, ErrorInvalidSchedule(expression, err.Error())
+	}
+
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1 << 0
+	}
+
+	return &schedule, nil
+}
+
+func MustParseSchedule(expression string) *Schedule {
+	schedule, err := ParseSchedule(expression)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func (schedule *Schedule) String() string {
+	return schedule.expression
+}
+
+// Matches returns whether the schedule fires during the minute containing t (in t's location)
+func (schedule *Schedule) Matches(t time.Time) bool {
+	if schedule.minutes&(1<<uint(t.Minute())) == 0 {
+		return false
+	}
+	if schedule.hours&(1<<uint(t.Hour())) == 0 {
+		return false
+	}
+	if schedule.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonthMatches := schedule.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeekMatches := schedule.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	// as in standard cron, if both day fields are restricted, either one can match
+	if !schedule.anyDayOfMonth && !schedule.anyDayOfWeek {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+	return dayOfMonthMatches && dayOfWeekMatches
+}
+
+// Last returns the most recent time at or before t at which the schedule fired, searching back no further than since
+// Returns nil if the schedule did not fire in that period
+func (schedule *Schedule) Last(t time.Time, since time.Time) *time.Time {
+	for minute := t.Truncate(time.Minute); !minute.Before(since.Truncate(time.Minute)); minute = minute.Add(-time.Minute) {
+		if schedule.Matches(minute) {
+			return &minute
+		}
+	}
+	return nil
+}
+
+func (field scheduleField) parse(str string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(str, ",") {
+		itemBits, err := field.parseItem(item)
+		if err != nil {
+			return 0, err
+		}
+		bits |= itemBits
+	}
+	return bits, nil
+}
+
+// Parses "*", "*/step", "value", "start-end", "start-end/step", or "start/step"
+func (field scheduleField) parseItem(item string) (uint64, error) {
+	rangeStr := item
+	step := 1
+
+	if slashIndex := strings.Index(item, "/"); slashIndex != -1 {
+		rangeStr = item[:slashIndex]
+		var err error
+		step, err = strconv.Atoi(item[slashIndex+1:])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %s field: \"%s\"", field.name, item)
+		}
+	}
+
+	var start, end int
+	switch {
+	case rangeStr == "*":
+		start, end = field.min, field.max
+	case strings.Contains(rangeStr, "-"):
+		bounds := strings.SplitN(rangeStr, "-", 2)
+		var err error
+		if start, err = field.parseValue(bounds[0]); err != nil {
+			return 0, err
+		}
+		if end, err = field.parseValue(bounds[1]); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range in %s field: \"%s\"", field.name, item)
+		}
+	default:
+		var err error
+		if start, err = field.parseValue(rangeStr); err != nil {
+			return 0, err
+		}
+		end = start
+		if rangeStr != item {
+			end = field.max
+		}
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+func (field scheduleField) parseValue(str string) (int, error) {
+	if val, ok := field.names[strings.ToLower(str)]; ok {
+		return val, nil
+	}
+
+	val, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field: \"%s\"", field.name, str)
+	}
+	if val < field.min || val > field.max {
+		return 0, fmt.Errorf("%s must be between %d and %d (got %d)", field.name, field.min, field.max, val)
+	}
+	return val, nil
+}
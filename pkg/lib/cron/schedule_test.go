@@ -0,0 +1,104 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseTime(str string) time.Time {
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseSchedule(t *testing.T) {
+	for _, expression := range []string{
+		"* * * * *",
+		"0 8 * * 1-5",
+		"*/15 0-6,20-23 1,15 jan-mar SUN",
+		"30 12 * * 7",
+		"5/10 * * * *",
+		"@daily",
+		"@HOURLY",
+	} {
+		_, err := ParseSchedule(expression)
+		require.NoError(t, err, expression)
+	}
+
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+	} {
+		_, err := ParseSchedule(expression)
+		require.Error(t, err, expression)
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	weekdayMornings := MustParseSchedule("0 8 * * mon-fri")
+	require.True(t, weekdayMornings.Matches(mustParseTime("2020-09-07T08:00:00Z")))  // monday
+	require.True(t, weekdayMornings.Matches(mustParseTime("2020-09-11T08:00:59Z")))  // friday
+	require.False(t, weekdayMornings.Matches(mustParseTime("2020-09-12T08:00:00Z"))) // saturday
+	require.False(t, weekdayMornings.Matches(mustParseTime("2020-09-07T08:01:00Z")))
+	require.False(t, weekdayMornings.Matches(mustParseTime("2020-09-07T09:00:00Z")))
+
+	sundays := MustParseSchedule("0 0 * * 7")
+	require.True(t, sundays.Matches(mustParseTime("2020-09-13T00:00:00Z")))
+	require.False(t, sundays.Matches(mustParseTime("2020-09-14T00:00:00Z")))
+
+	everyTenMinutes := MustParseSchedule("5/10 * * * *")
+	require.True(t, everyTenMinutes.Matches(mustParseTime("2020-09-07T10:05:00Z")))
+	require.True(t, everyTenMinutes.Matches(mustParseTime("2020-09-07T10:55:00Z")))
+	require.False(t, everyTenMinutes.Matches(mustParseTime("2020-09-07T10:00:00Z")))
+
+	// if both day fields are restricted, either one can match
+	firstOrMonday := MustParseSchedule("0 0 1 * 1")
+	require.True(t, firstOrMonday.Matches(mustParseTime("2020-09-01T00:00:00Z"))) // tuesday
+	require.True(t, firstOrMonday.Matches(mustParseTime("2020-09-07T00:00:00Z"))) // monday
+	require.False(t, firstOrMonday.Matches(mustParseTime("2020-09-08T00:00:00Z")))
+}
+
+func TestScheduleLast(t *testing.T) {
+	weekdayMornings := MustParseSchedule("0 8 * * 1-5")
+
+	now := mustParseTime("2020-09-07T19:59:30Z")
+	last := weekdayMornings.Last(now, now.Add(-12*time.Hour))
+	require.NotNil(t, last)
+	require.Equal(t, mustParseTime("2020-09-07T08:00:00Z"), *last)
+
+	now = mustParseTime("2020-09-07T20:00:00Z")
+	require.Nil(t, weekdayMornings.Last(now, now.Add(-11*time.Hour-59*time.Minute)))
+
+	now = mustParseTime("2020-09-12T10:00:00Z") // saturday
+	require.Nil(t, weekdayMornings.Last(now, now.Add(-12*time.Hour)))
+}
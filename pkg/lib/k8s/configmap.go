@@ -69,6 +69,32 @@ func (c *Client) UpdateConfigMap(configMap *kcore.ConfigMap) (*kcore.ConfigMap,
 	return configMap, nil
 }
 
+// CreateConfigMapIfNotExists returns false (without an error) if a config map with the same name already exists
+func (c *Client) CreateConfigMapIfNotExists(configMap *kcore.ConfigMap) (bool, error) {
+	configMap.TypeMeta = _configMapTypeMeta
+	_, err := c.configMapClient.Create(context.Background(), configMap, kmeta.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// UpdateConfigMapIfUnchanged returns false (without an error) if the config map was modified since it was retrieved (i.e. its resource version no longer matches)
+func (c *Client) UpdateConfigMapIfUnchanged(configMap *kcore.ConfigMap) (bool, error) {
+	configMap.TypeMeta = _configMapTypeMeta
+	_, err := c.configMapClient.Update(context.Background(), configMap, kmeta.UpdateOptions{})
+	if kerrors.IsConflict(err) || kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
 func (c *Client) ApplyConfigMap(configMap *kcore.ConfigMap) (*kcore.ConfigMap, error) {
 	existing, err := c.GetConfigMap(configMap.Name)
 	if err != nil {
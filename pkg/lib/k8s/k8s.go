@@ -50,7 +50,7 @@ var (
 
 type Client struct {
 	RestConfig           *kclientrest.Config
-	clientset            *kclientset.Clientset
+	clientset            kclientset.Interface
 	dynamicClient        kclientdynamic.Interface
 	podClient            kclientcore.PodInterface
 	nodeClient           kclientcore.NodeInterface
@@ -80,10 +80,11 @@ func New(namespace string, inCluster bool) (*Client, error) {
 		return nil, errors.Wrap(err, "kubeconfig")
 	}
 
-	client.clientset, err = kclientset.NewForConfig(client.RestConfig)
+	clientset, err := kclientset.NewForConfig(client.RestConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "kubeconfig")
 	}
+	client.setClientset(clientset)
 
 	client.dynamicClient, err = kclientdynamic.NewForConfig(client.RestConfig)
 	if err != nil {
@@ -96,17 +97,30 @@ func New(namespace string, inCluster bool) (*Client, error) {
 	}
 	client.virtualServiceClient = istioClient.NetworkingV1alpha3().VirtualServices(namespace)
 
-	client.podClient = client.clientset.CoreV1().Pods(namespace)
-	client.nodeClient = client.clientset.CoreV1().Nodes()
-	client.serviceClient = client.clientset.CoreV1().Services(namespace)
-	client.configMapClient = client.clientset.CoreV1().ConfigMaps(namespace)
-	client.deploymentClient = client.clientset.AppsV1().Deployments(namespace)
-	client.jobClient = client.clientset.BatchV1().Jobs(namespace)
-	client.ingressClient = client.clientset.ExtensionsV1beta1().Ingresses(namespace)
-	client.hpaClient = client.clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace)
 	return client, nil
 }
 
+// NewForClientset creates a client which uses an existing clientset (e.g. a fake clientset in tests); the dynamic and istio clients are not initialized
+func NewForClientset(namespace string, clientset kclientset.Interface) *Client {
+	client := &Client{
+		Namespace: namespace,
+	}
+	client.setClientset(clientset)
+	return client
+}
+
+func (c *Client) setClientset(clientset kclientset.Interface) {
+	c.clientset = clientset
+	c.podClient = clientset.CoreV1().Pods(c.Namespace)
+	c.nodeClient = clientset.CoreV1().Nodes()
+	c.serviceClient = clientset.CoreV1().Services(c.Namespace)
+	c.configMapClient = clientset.CoreV1().ConfigMaps(c.Namespace)
+	c.deploymentClient = clientset.AppsV1().Deployments(c.Namespace)
+	c.jobClient = clientset.BatchV1().Jobs(c.Namespace)
+	c.ingressClient = clientset.ExtensionsV1beta1().Ingresses(c.Namespace)
+	c.hpaClient = clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(c.Namespace)
+}
+
 // to be safe, k8s sometimes needs all characters to be lower case, and the first to be a letter
 func RandomName() string {
 	return random.LowercaseLetters(1) + random.LowercaseString(62)
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"math"
+	"time"
+
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// ActivationReplicaRequest is the number of replicas that an API which has been scaled to zero is scaled back up to
+const ActivationReplicaRequest = int32(1)
+
+const (
+	_messageActivating          = "activating"
+	_messageScaledToZero        = "scaled to zero"
+	_messageMetricsNotAvailable = "metrics not available yet"
+)
+
+// State is the autoscaler's state, which carries over from one tick to the next
+type State struct {
+	StartTime       time.Time
+	LastActiveTime  time.Time
+	CurrentReplicas int32
+	Recommendations Recommendations
+	SeasonalProfile *SeasonalProfile // only used if predictive scaling is enabled
+}
+
+func NewState(startTime time.Time, currentReplicas int32) *State {
+	return &State{
+		StartTime:       startTime,
+		LastActiveTime:  startTime,
+		CurrentReplicas: currentReplicas,
+		Recommendations: make(Recommendations),
+	}
+}
+
+// Decide makes the autoscaler's decision for a tick. It doesn't change state.CurrentReplicas, which should be updated once the
+// decision's request has been applied. If the API is scaled to zero, it is only activated if min_replicas requires it (otherwise
+// the activator scales it up when it receives a request). If avgInFlight is nil (i.e. metrics are not available), the current
+// number of replicas is requested.
+func Decide(autoscalingSpec *userconfig.Autoscaling, state *State, now time.Time, avgInFlight *float64, policyRecs []schema.AutoscalerPolicyRecommendation) schema.AutoscalerDecision {
+	activeSchedule := autoscalingSpec.ActiveSchedule(now)
+	minReplicas, maxReplicas := autoscalingSpec.ReplicaBoundsAt(now)
+	currentReplicas := state.CurrentReplicas
+
+	decision := schema.AutoscalerDecision{
+		Timestamp:                now,
+		TargetReplicaConcurrency: *autoscalingSpec.TargetReplicaConcurrency,
+		CurrentReplicas:          currentReplicas,
+		MaxReplicas:              maxReplicas,
+	}
+	if activeSchedule != nil {
+		decision.ActiveSchedule = &activeSchedule.Schedule
+	}
+
+	// raise the floor ahead of the traffic which is expected based on the same time in previous weeks
+	if autoscalingSpec.PredictiveScaling && state.SeasonalProfile != nil {
+		predictedInFlight := state.SeasonalProfile.predict(now, autoscalingSpec.PredictiveScalingLeadTime)
+		if predictedInFlight != nil {
+			predictiveFloor := libmath.MinInt32(int32(math.Ceil(*predictedInFlight / *autoscalingSpec.TargetReplicaConcurrency)), maxReplicas)
+			if predictiveFloor > minReplicas {
+				minReplicas = predictiveFloor
+			}
+			decision.PredictedInFlight = predictedInFlight
+			decision.PredictiveFloor = &predictiveFloor
+		}
+	}
+	decision.MinReplicas = minReplicas
+
+	if currentReplicas == 0 {
+		if minReplicas > 0 {
+			// e.g. a scheduled scaling window requires the API to be running
+			decision.Message = _messageActivating
+			decision.Request = ActivationReplicaRequest
+		} else {
+			decision.Message = _messageScaledToZero
+		}
+		decision.IdleTime = now.Sub(state.LastActiveTime).Truncate(time.Second)
+		return decision
+	}
+
+	if avgInFlight == nil {
+		decision.Message = _messageMetricsNotAvailable
+		decision.Request = currentReplicas
+		return decision
+	}
+
+	if *avgInFlight > 0 {
+		state.LastActiveTime = now
+	}
+
+	if state.SeasonalProfile != nil {
+		state.SeasonalProfile.add(now, *avgInFlight)
+	}
+
+	rawRecommendation := *avgInFlight / *autoscalingSpec.TargetReplicaConcurrency
+
+	// when multiple policies are configured, the one which recommends the most replicas is used
+	for _, policyRec := range policyRecs {
+		if policyRec.RawRecommendation > rawRecommendation {
+			rawRecommendation = policyRec.RawRecommendation
+		}
+	}
+
+	recommendation := int32(math.Ceil(rawRecommendation))
+
+	if rawRecommendation < float64(currentReplicas) && rawRecommendation > float64(currentReplicas)*(1-autoscalingSpec.DownscaleTolerance) {
+		recommendation = currentReplicas
+	}
+
+	if rawRecommendation > float64(currentReplicas) && rawRecommendation < float64(currentReplicas)*(1+autoscalingSpec.UpscaleTolerance) {
+		recommendation = currentReplicas
+	}
+
+	// always allow subtraction of 1
+	downscaleFactorFloor := libmath.MinInt32(currentReplicas-1, int32(math.Ceil(float64(currentReplicas)*autoscalingSpec.MaxDownscaleFactor)))
+	if recommendation < downscaleFactorFloor {
+		recommendation = downscaleFactorFloor
+	}
+
+	// always allow addition of 1
+	upscaleFactorCeil := libmath.MaxInt32(currentReplicas+1, int32(math.Ceil(float64(currentReplicas)*autoscalingSpec.MaxUpscaleFactor)))
+	if recommendation > upscaleFactorCeil {
+		recommendation = upscaleFactorCeil
+	}
+
+	// scaling to zero is only done after the API has been idle for the scale to zero period (see below)
+	if recommendation < 1 {
+		recommendation = 1
+	}
+
+	if recommendation < minReplicas {
+		recommendation = minReplicas
+	}
+
+	if recommendation > maxReplicas {
+		recommendation = maxReplicas
+	}
+
+	// Rule of thumb: any modifications that don't consider historical recommendations should be performed before
+	// recording the recommendation, any modifications that use historical recommendations should be performed after
+	state.Recommendations.add(now, recommendation)
+
+	// This is just for garbage collection
+	state.Recommendations.deleteOlderThan(now, libtime.MaxDuration(autoscalingSpec.DownscaleStabilizationPeriod, autoscalingSpec.UpscaleStabilizationPeriod))
+
+	request := recommendation
+
+	downscaleStabilizationFloor := state.Recommendations.maxSince(now, autoscalingSpec.DownscaleStabilizationPeriod)
+	if now.Sub(state.StartTime) < autoscalingSpec.DownscaleStabilizationPeriod {
+		if request < currentReplicas {
+			request = currentReplicas
+		}
+	} else if downscaleStabilizationFloor != nil && request < *downscaleStabilizationFloor {
+		request = *downscaleStabilizationFloor
+	}
+
+	upscaleStabilizationCeil := state.Recommendations.minSince(now, autoscalingSpec.UpscaleStabilizationPeriod)
+	if now.Sub(state.StartTime) < autoscalingSpec.UpscaleStabilizationPeriod {
+		if request > currentReplicas {
+			request = currentReplicas
+		}
+	} else if upscaleStabilizationCeil != nil && request > *upscaleStabilizationCeil {
+		request = *upscaleStabilizationCeil
+	}
+
+	// scheduled scaling windows are applied immediately so that replicas are warm by the time that traffic arrives
+	if request < minReplicas {
+		request = minReplicas
+	}
+
+	if request > maxReplicas {
+		request = maxReplicas
+	}
+
+	idleTime := now.Sub(state.LastActiveTime)
+	if minReplicas == 0 && idleTime >= autoscalingSpec.ScaleToZeroPeriod {
+		request = 0
+	}
+
+	decision.AvgInFlight = avgInFlight
+	decision.Policies = policyRecs
+	decision.RawRecommendation = rawRecommendation
+	decision.DownscaleFactorFloor = downscaleFactorFloor
+	decision.UpscaleFactorCeil = upscaleFactorCeil
+	decision.Recommendation = recommendation
+	decision.DownscaleStabilizationFloor = downscaleStabilizationFloor
+	decision.UpscaleStabilizationCeil = upscaleStabilizationCeil
+	decision.IdleTime = idleTime.Truncate(time.Second)
+	decision.Request = request
+
+	return decision
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+var _now = time.Date(2020, 9, 7, 8, 30, 0, 0, time.UTC)
+
+func testAutoscalingSpec() *userconfig.Autoscaling {
+	return &userconfig.Autoscaling{
+		MinReplicas:                  1,
+		MaxReplicas:                  100,
+		InitReplicas:                 1,
+		TargetReplicaConcurrency:     pointer.Float64(1),
+		Window:                       time.Minute,
+		DownscaleStabilizationPeriod: 5 * time.Minute,
+		UpscaleStabilizationPeriod:   time.Minute,
+		MaxDownscaleFactor:           0.75,
+		MaxUpscaleFactor:             1.5,
+		DownscaleTolerance:           0.05,
+		UpscaleTolerance:             0.05,
+		ScaleToZeroPeriod:            10 * time.Minute,
+	}
+}
+
+// a state which has been running for long enough that the stabilization periods don't apply
+func testState(currentReplicas int32) *State {
+	return NewState(_now.Add(-time.Hour), currentReplicas)
+}
+
+func TestDecideTolerance(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+
+	decision := Decide(autoscalingSpec, testState(10), _now, pointer.Float64(10.4), nil)
+	require.Equal(t, int32(10), decision.Recommendation)
+	require.Equal(t, int32(10), decision.Request)
+
+	decision = Decide(autoscalingSpec, testState(10), _now, pointer.Float64(9.6), nil)
+	require.Equal(t, int32(10), decision.Recommendation)
+
+	decision = Decide(autoscalingSpec, testState(10), _now, pointer.Float64(10.6), nil)
+	require.Equal(t, int32(11), decision.Recommendation)
+	require.Equal(t, int32(11), decision.Request)
+}
+
+func TestDecideScaleFactors(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+
+	decision := Decide(autoscalingSpec, testState(10), _now, pointer.Float64(100), nil)
+	require.Equal(t, int32(15), decision.UpscaleFactorCeil)
+	require.Equal(t, int32(15), decision.Request)
+
+	decision = Decide(autoscalingSpec, testState(10), _now, pointer.Float64(1), nil)
+	require.Equal(t, int32(8), decision.DownscaleFactorFloor)
+	require.Equal(t, int32(8), decision.Request)
+
+	// addition of 1 is always allowed
+	decision = Decide(autoscalingSpec, testState(1), _now, pointer.Float64(100), nil)
+	require.Equal(t, int32(2), decision.Request)
+}
+
+func TestDecideStabilization(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+
+	// the upscale stabilization ceiling (which includes the latest recommendation) is applied after the downscale stabilization floor
+	state := testState(20)
+	state.Recommendations.add(_now.Add(-2*time.Minute), 20)
+	decision := Decide(autoscalingSpec, state, _now, pointer.Float64(5), nil)
+	require.Equal(t, int32(15), decision.Recommendation)
+	require.Equal(t, pointer.Int32(20), decision.DownscaleStabilizationFloor)
+	require.Equal(t, pointer.Int32(15), decision.UpscaleStabilizationCeil)
+	require.Equal(t, int32(15), decision.Request)
+
+	state = testState(10)
+	state.Recommendations.add(_now.Add(-30*time.Second), 10)
+	decision = Decide(autoscalingSpec, state, _now, pointer.Float64(20), nil)
+	require.Equal(t, int32(15), decision.Recommendation)
+	require.Equal(t, pointer.Int32(10), decision.UpscaleStabilizationCeil)
+	require.Equal(t, int32(10), decision.Request)
+
+	// recommendations which are older than both stabilization periods are discarded
+	state = testState(10)
+	state.Recommendations.add(_now.Add(-10*time.Minute), 50)
+	Decide(autoscalingSpec, state, _now, pointer.Float64(10), nil)
+	require.Equal(t, 1, len(state.Recommendations))
+
+	// the current number of replicas is kept until the autoscaler has been running for the stabilization period
+	state = NewState(_now.Add(-30*time.Second), 10)
+	decision = Decide(autoscalingSpec, state, _now, pointer.Float64(20), nil)
+	require.Equal(t, int32(15), decision.Recommendation)
+	require.Equal(t, int32(10), decision.Request)
+}
+
+func TestDecideReplicaBounds(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.MinReplicas = 3
+	autoscalingSpec.MaxReplicas = 12
+
+	decision := Decide(autoscalingSpec, testState(10), _now, pointer.Float64(30), nil)
+	require.Equal(t, int32(12), decision.Request)
+
+	decision = Decide(autoscalingSpec, testState(3), _now, pointer.Float64(0), nil)
+	require.Equal(t, int32(3), decision.Request)
+
+	// scheduled scaling windows are applied regardless of the stabilization periods
+	autoscalingSpec.Schedules = []*userconfig.AutoscalingSchedule{
+		{
+			Schedule:    "0 8 * * *",
+			Duration:    time.Hour,
+			MinReplicas: pointer.Int32(10),
+		},
+	}
+	decision = Decide(autoscalingSpec, NewState(_now, 3), _now, pointer.Float64(0), nil)
+	require.Equal(t, pointer.String("0 8 * * *"), decision.ActiveSchedule)
+	require.Equal(t, int32(10), decision.MinReplicas)
+	require.Equal(t, int32(10), decision.Request)
+}
+
+func TestDecidePolicies(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+
+	policyRecs := []schema.AutoscalerPolicyRecommendation{
+		{Policy: userconfig.TargetP95LatencyKey, Metric: 400, Target: 200, RawRecommendation: 12},
+		{Policy: userconfig.TargetRequestsPerSecondKey, Metric: 50, Target: 10, RawRecommendation: 5},
+	}
+	decision := Decide(autoscalingSpec, testState(10), _now, pointer.Float64(4), policyRecs)
+	require.Equal(t, 12.0, decision.RawRecommendation)
+	require.Equal(t, int32(12), decision.Request)
+}
+
+func TestDecideScaleToZero(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.MinReplicas = 0
+
+	state := testState(1)
+	state.LastActiveTime = _now.Add(-time.Minute)
+	decision := Decide(autoscalingSpec, state, _now, pointer.Float64(0), nil)
+	require.Equal(t, int32(1), decision.Request)
+
+	state.LastActiveTime = _now.Add(-10 * time.Minute)
+	decision = Decide(autoscalingSpec, state, _now, pointer.Float64(0), nil)
+	require.Equal(t, int32(0), decision.Request)
+
+	decision = Decide(autoscalingSpec, state, _now, pointer.Float64(0.5), nil)
+	require.Equal(t, int32(1), decision.Request)
+	require.Equal(t, _now, state.LastActiveTime)
+
+	state = testState(0)
+	decision = Decide(autoscalingSpec, state, _now, nil, nil)
+	require.Equal(t, _messageScaledToZero, decision.Message)
+	require.Equal(t, int32(0), decision.Request)
+
+	autoscalingSpec.MinReplicas = 2
+	decision = Decide(autoscalingSpec, state, _now, nil, nil)
+	require.Equal(t, _messageActivating, decision.Message)
+	require.Equal(t, ActivationReplicaRequest, decision.Request)
+}
+
+func TestDecidePredictiveScaling(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.PredictiveScaling = true
+	autoscalingSpec.PredictiveScalingLeadTime = 15 * time.Minute
+
+	// 20 in-flight requests during the 8am hour of the previous monday
+	profile := NewSeasonalProfile()
+	for i := 0; i < _seasonalProfileMinSamples; i++ {
+		profile.add(_now.Add(-7*24*time.Hour), 20)
+	}
+
+	state := testState(2)
+	state.SeasonalProfile = profile
+	decision := Decide(autoscalingSpec, state, _now.Add(-70*time.Minute), pointer.Float64(2), nil)
+	require.Nil(t, decision.PredictiveFloor)
+	require.Equal(t, int32(2), decision.Request)
+
+	// the 8am hour is within the lead time
+	decision = Decide(autoscalingSpec, state, _now.Add(-40*time.Minute), pointer.Float64(2), nil)
+	require.Equal(t, pointer.Float64(20), decision.PredictedInFlight)
+	require.Equal(t, pointer.Int32(20), decision.PredictiveFloor)
+	require.Equal(t, int32(20), decision.MinReplicas)
+	require.Equal(t, int32(20), decision.Request)
+
+	autoscalingSpec.MaxReplicas = 12
+	decision = Decide(autoscalingSpec, state, _now, pointer.Float64(2), nil)
+	require.Equal(t, pointer.Int32(12), decision.PredictiveFloor)
+	require.Equal(t, int32(12), decision.Request)
+
+	// an API which has been scaled to zero is activated ahead of the expected traffic
+	autoscalingSpec.MinReplicas = 0
+	decision = Decide(autoscalingSpec, testState(0), _now, nil, nil)
+	require.Equal(t, _messageScaledToZero, decision.Message)
+
+	state = testState(0)
+	state.SeasonalProfile = profile
+	decision = Decide(autoscalingSpec, state, _now, nil, nil)
+	require.Equal(t, _messageActivating, decision.Message)
+	require.Equal(t, ActivationReplicaRequest, decision.Request)
+}
+
+func TestSeasonalProfile(t *testing.T) {
+	profile := NewSeasonalProfile()
+	require.Nil(t, profile.predict(_now, time.Hour))
+
+	for i := 0; i < _seasonalProfileMinSamples-1; i++ {
+		profile.add(_now, 10)
+	}
+	require.Nil(t, profile.predict(_now, 0))
+
+	profile.add(_now, 10)
+	require.Equal(t, pointer.Float64(10), profile.predict(_now, 0))
+
+	// the same hour of the following week
+	for i := 0; i < _seasonalProfileMinSamples; i++ {
+		profile.add(_now.Add(7*24*time.Hour), 30)
+	}
+	require.InDelta(t, 20, *profile.predict(_now, 0), 0.001)
+
+	// the highest average from now through the lead time is used
+	for i := 0; i < _seasonalProfileMinSamples; i++ {
+		profile.add(_now.Add(time.Hour), 50)
+	}
+	require.InDelta(t, 20, *profile.predict(_now, 29*time.Minute), 0.001)
+	require.InDelta(t, 50, *profile.predict(_now, 30*time.Minute), 0.001)
+
+	// the number of samples is capped so that recent weeks continue to have an effect
+	for i := 0; i < 8*_seasonalProfileMaxSamples; i++ {
+		profile.add(_now.Add(time.Hour), 0)
+	}
+	require.Equal(t, _seasonalProfileMaxSamples, profile.NumSamples[hourOfWeek(_now.Add(time.Hour))])
+	require.InDelta(t, 0, *profile.predict(_now.Add(time.Hour), 0), 0.01)
+}
+
+func TestDecideMetricsNotAvailable(t *testing.T) {
+	state := testState(4)
+	decision := Decide(testAutoscalingSpec(), state, _now, nil, nil)
+	require.Equal(t, _messageMetricsNotAvailable, decision.Message)
+	require.Equal(t, int32(4), decision.Request)
+	require.Equal(t, 0, len(state.Recommendations))
+}
+
+func TestSimulate(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.MinReplicas = 0
+	autoscalingSpec.Window = 10 * time.Second
+	autoscalingSpec.DownscaleStabilizationPeriod = 0
+	autoscalingSpec.UpscaleStabilizationPeriod = 0
+	autoscalingSpec.ScaleToZeroPeriod = time.Minute
+
+	// idle for 3 minutes, and then 10 in-flight requests for 2 minutes
+	var samples []Sample
+	for i := 0; i <= 30; i++ {
+		sample := Sample{Timestamp: _now.Add(time.Duration(i) * 10 * time.Second)}
+		if i >= 18 {
+			sample.InFlight = 10
+		}
+		samples = append(samples, sample)
+	}
+
+	decisions := Simulate(autoscalingSpec, samples, 10*time.Second)
+	require.Equal(t, 31, len(decisions))
+
+	require.Equal(t, int32(1), decisions[5].Request)
+	require.Equal(t, int32(0), decisions[6].Request)
+	require.Equal(t, _messageScaledToZero, decisions[7].Message)
+	require.Equal(t, int32(0), decisions[17].Request)
+
+	// the activator brings the API back up, and then it is scaled up as quickly as max_upscale_factor allows
+	require.Equal(t, int32(1), decisions[18].CurrentReplicas)
+	requests := make([]int32, 0, 6)
+	for _, decision := range decisions[18:24] {
+		requests = append(requests, decision.Request)
+	}
+	require.Equal(t, []int32{2, 3, 5, 8, 10, 10}, requests)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	ErrInvalidSample = "autoscaler.invalid_sample"
+	ErrNoSamples     = "autoscaler.no_samples"
+)
+
+func ErrorInvalidSample(location string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSample,
+		Message: fmt.Sprintf("%s: %s (each sample must have a timestamp, which is either an RFC 3339 string or a unix timestamp in seconds, and a non-negative number of in-flight requests)", location, reason),
+	})
+}
+
+func ErrorNoSamples() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoSamples,
+		Message: "the series does not contain any samples",
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"time"
+)
+
+const (
+	_hoursPerWeek = 7 * 24
+
+	// the average for each hour adapts to changes in traffic over about four weeks of ticks
+	_seasonalProfileMaxSamples = 4 * 360
+
+	// an hour is only used for predictions once it has been observed for about half an hour
+	_seasonalProfileMinSamples = 180
+)
+
+// SeasonalProfile is an API's average number of in-flight requests during each hour of the week (in UTC)
+type SeasonalProfile struct {
+	AvgInFlight [_hoursPerWeek]float64 `json:"avg_in_flight"`
+	NumSamples  [_hoursPerWeek]int     `json:"num_samples"`
+}
+
+func NewSeasonalProfile() *SeasonalProfile {
+	return &SeasonalProfile{}
+}
+
+func hourOfWeek(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+func (profile *SeasonalProfile) add(t time.Time, inFlight float64) {
+	hour := hourOfWeek(t)
+
+	if profile.NumSamples[hour] < _seasonalProfileMaxSamples {
+		profile.NumSamples[hour]++
+	}
+
+	profile.AvgInFlight[hour] += (inFlight - profile.AvgInFlight[hour]) / float64(profile.NumSamples[hour])
+}
+
+// Returns the highest hourly average from the hour which contains t through the hour which contains t+leadTime, or nil if none of those hours have been observed for long enough
+func (profile *SeasonalProfile) predict(t time.Time, leadTime time.Duration) *float64 {
+	var prediction *float64
+
+	for hourStart := t.Truncate(time.Hour); !hourStart.After(t.Add(leadTime)); hourStart = hourStart.Add(time.Hour) {
+		hour := hourOfWeek(hourStart)
+		if profile.NumSamples[hour] < _seasonalProfileMinSamples {
+			continue
+		}
+		if prediction == nil || profile.AvgInFlight[hour] > *prediction {
+			avgInFlight := profile.AvgInFlight[hour]
+			prediction = &avgInFlight
+		}
+	}
+
+	return prediction
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+// Sample is a recorded observation of the total number of in-flight requests across all of an API's replicas
+type Sample struct {
+	Timestamp time.Time
+	InFlight  float64
+}
+
+// ParseCSVSeries parses rows of "timestamp,in_flight" (an optional header row is skipped); the returned samples are sorted by timestamp
+func ParseCSVSeries(seriesBytes []byte) ([]Sample, error) {
+	reader := csv.NewReader(bytes.NewReader(seriesBytes))
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var samples []Sample
+	for lineNum := 1; ; lineNum++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		location := fmt.Sprintf("line %d", lineNum)
+
+		timestamp, ok := parseTimestamp(record[0])
+		if !ok {
+			if lineNum == 1 {
+				continue // header
+			}
+			return nil, ErrorInvalidSample(location, fmt.Sprintf("\"%s\" is not a valid timestamp", record[0]))
+		}
+
+		inFlight, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, ErrorInvalidSample(location, fmt.Sprintf("\"%s\" is not a valid number of in-flight requests", record[1]))
+		}
+		if inFlight < 0 {
+			return nil, ErrorInvalidSample(location, "the number of in-flight requests must be non-negative")
+		}
+
+		samples = append(samples, Sample{Timestamp: timestamp, InFlight: inFlight})
+	}
+
+	return sortSamples(samples)
+}
+
+// ParseJSONSeries parses a list of objects with "timestamp" and "in_flight" fields; the returned samples are sorted by timestamp
+func ParseJSONSeries(seriesBytes []byte) ([]Sample, error) {
+	var records []struct {
+		Timestamp json.RawMessage `json:"timestamp"`
+		InFlight  *float64        `json:"in_flight"`
+	}
+	if err := json.Unmarshal(seriesBytes, &records); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	samples := make([]Sample, len(records))
+	for i, record := range records {
+		location := fmt.Sprintf("sample at index %d", i)
+
+		var timestampStr string
+		if err := json.Unmarshal(record.Timestamp, &timestampStr); err != nil {
+			timestampStr = string(record.Timestamp) // numeric timestamps aren't quoted
+		}
+		timestamp, ok := parseTimestamp(timestampStr)
+		if !ok {
+			return nil, ErrorInvalidSample(location, fmt.Sprintf("\"%s\" is not a valid timestamp", timestampStr))
+		}
+
+		if record.InFlight == nil {
+			return nil, ErrorInvalidSample(location, "in_flight is missing")
+		}
+		if *record.InFlight < 0 {
+			return nil, ErrorInvalidSample(location, "the number of in-flight requests must be non-negative")
+		}
+
+		samples[i] = Sample{Timestamp: timestamp, InFlight: *record.InFlight}
+	}
+
+	return sortSamples(samples)
+}
+
+// Accepts RFC 3339 strings and unix timestamps (in seconds)
+func parseTimestamp(str string) (time.Time, bool) {
+	str = strings.TrimSpace(str)
+
+	if seconds, err := strconv.ParseFloat(str, 64); err == nil {
+		wholeSeconds, fraction := math.Modf(seconds)
+		return time.Unix(int64(wholeSeconds), int64(fraction*float64(time.Second))).UTC(), true
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return timestamp.UTC(), true
+}
+
+func sortSamples(samples []Sample) ([]Sample, error) {
+	if len(samples) == 0 {
+		return nil, ErrorNoSamples()
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+
+	return samples, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSVSeries(t *testing.T) {
+	samples, err := ParseCSVSeries([]byte("timestamp,in_flight\n2020-09-07T08:00:10Z,2\n1599465600, 1.5\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Sample{
+		{Timestamp: time.Date(2020, 9, 7, 8, 0, 0, 0, time.UTC), InFlight: 1.5},
+		{Timestamp: time.Date(2020, 9, 7, 8, 0, 10, 0, time.UTC), InFlight: 2},
+	}, samples)
+
+	samples, err = ParseCSVSeries([]byte("2020-09-07T08:00:00Z,0\n"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(samples))
+
+	for _, series := range []string{
+		"",
+		"timestamp,in_flight\n",
+		"2020-09-07T08:00:00Z,-1\n",
+		"2020-09-07T08:00:00Z,abc\n",
+		"2020-09-07T08:00:00Z\n",
+		"2020-09-07T08:00:00Z,1\nyesterday,1\n",
+	} {
+		_, err := ParseCSVSeries([]byte(series))
+		require.Error(t, err, series)
+	}
+}
+
+func TestParseJSONSeries(t *testing.T) {
+	samples, err := ParseJSONSeries([]byte(`[{"timestamp": "2020-09-07T08:00:10Z", "in_flight": 2}, {"timestamp": 1599465600, "in_flight": 1.5}]`))
+	require.NoError(t, err)
+	require.Equal(t, []Sample{
+		{Timestamp: time.Date(2020, 9, 7, 8, 0, 0, 0, time.UTC), InFlight: 1.5},
+		{Timestamp: time.Date(2020, 9, 7, 8, 0, 10, 0, time.UTC), InFlight: 2},
+	}, samples)
+
+	for _, series := range []string{
+		`[]`,
+		`{"timestamp": 1599465600, "in_flight": 1}`,
+		`[{"timestamp": 1599465600}]`,
+		`[{"in_flight": 1}]`,
+		`[{"timestamp": "yesterday", "in_flight": 1}]`,
+		`[{"timestamp": 1599465600, "in_flight": -1}]`,
+	} {
+		_, err := ParseJSONSeries([]byte(series))
+		require.Error(t, err, series)
+	}
+}
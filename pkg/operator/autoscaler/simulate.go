@@ -0,0 +1,82 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// Simulate replays the samples (which must be sorted by timestamp) through the autoscaler, ticking once per tickInterval.
+// The API starts with init_replicas (and, if predictive scaling is enabled, with an empty seasonal profile), and each request is
+// assumed to take effect before the next tick. The optional autoscaling policies are not simulated, since their metrics depend
+// on the number of replicas that were running.
+func Simulate(autoscalingSpec *userconfig.Autoscaling, samples []Sample, tickInterval time.Duration) []schema.AutoscalerDecision {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	startTime := samples[0].Timestamp
+	endTime := samples[len(samples)-1].Timestamp
+	state := NewState(startTime, autoscalingSpec.InitReplicas)
+	if autoscalingSpec.PredictiveScaling {
+		state.SeasonalProfile = NewSeasonalProfile()
+	}
+
+	decisions := make([]schema.AutoscalerDecision, 0, int(endTime.Sub(startTime)/tickInterval)+1)
+
+	// samples in the autoscaling window are samples[windowStart:windowEnd]
+	windowStart := 0
+	windowEnd := 0
+
+	for now := startTime; !now.After(endTime); now = now.Add(tickInterval) {
+		for windowEnd < len(samples) && !samples[windowEnd].Timestamp.After(now) {
+			windowEnd++
+		}
+		for windowStart < windowEnd && !samples[windowStart].Timestamp.After(now.Add(-autoscalingSpec.Window)) {
+			windowStart++
+		}
+
+		var avgInFlight *float64
+		if windowEnd > windowStart {
+			total := 0.0
+			for _, sample := range samples[windowStart:windowEnd] {
+				total += sample.InFlight
+			}
+			avg := total / float64(windowEnd-windowStart)
+			avgInFlight = &avg
+		}
+
+		// the activator scales the API back up as soon as it receives a request
+		if state.CurrentReplicas == 0 && avgInFlight != nil && *avgInFlight > 0 {
+			state.CurrentReplicas = ActivationReplicaRequest
+			state.LastActiveTime = now
+		}
+
+		decision := Decide(autoscalingSpec, state, now, avgInFlight, nil)
+		decisions = append(decisions, decision)
+
+		if decision.Message == _messageActivating {
+			state.LastActiveTime = now
+		}
+		state.CurrentReplicas = decision.Request
+	}
+
+	return decisions
+}
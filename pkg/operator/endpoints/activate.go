@@ -0,0 +1,73 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/gorilla/mux"
+)
+
+// Activate receives prediction requests for APIs which have been scaled to zero, holds them until a replica is ready, and forwards them
+func Activate(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if deployedResource.Kind != userconfig.RealtimeAPIKind {
+		respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind))
+		return
+	}
+
+	isAwaitingActivation, err := realtimeapi.IsAwaitingActivation(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if !isAwaitingActivation {
+		respondError(w, r, realtimeapi.ErrorAPINotScaledToZero(apiName))
+		return
+	}
+
+	if err := realtimeapi.ActivateAPI(apiName); err != nil {
+		respondErrorCode(w, r, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	target := &url.URL{
+		Scheme: "http",
+		Host:   operator.K8sName(apiName) + ":" + operator.DefaultPortStr,
+	}
+
+	proxyPredictRequest(w, r, target)
+}
+
+func proxyPredictRequest(w http.ResponseWriter, r *http.Request, target *url.URL) {
+	r.URL.Path = "/predict"
+	r.URL.RawPath = ""
+
+	httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyPredictRequest(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/predict", r.URL.Path)
+		require.Equal(t, "key=value", r.URL.RawQuery)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, `{"input": 1}`, string(body))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"output": 2}`))
+	}))
+	defer api.Close()
+
+	target, err := url.Parse(api.URL)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/activate/test?key=value", strings.NewReader(`{"input": 1}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	proxyPredictRequest(w, r, target)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, `{"output": 2}`, w.Body.String())
+}
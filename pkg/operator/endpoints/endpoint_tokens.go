@@ -0,0 +1,103 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/gorilla/mux"
+)
+
+func CreateEndpointToken(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, err := batchapi.CreateEndpointToken(apiName, r.URL.Query().Get("description"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, response)
+}
+
+func GetEndpointTokens(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	endpointAuthType, err := batchapi.GetEndpointAuthType(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	tokens, err := batchapi.GetEndpointTokens(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.GetEndpointTokensResponse{
+		APIName:      apiName,
+		EndpointAuth: endpointAuthType,
+		Tokens:       tokens,
+	})
+}
+
+func DeleteEndpointToken(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiName := vars["apiName"]
+	tokenID := vars["tokenID"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if err := batchapi.DeleteEndpointToken(apiName, tokenID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.DeleteResponse{
+		Message: fmt.Sprintf("deleted token %s", tokenID),
+	})
+}
+
+func validateBatchAPIIsDeployed(apiName string) error {
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		return err
+	}
+	if deployedResource.Kind != userconfig.BatchAPIKind {
+		return resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.BatchAPIKind)
+	}
+	return nil
+}
@@ -33,10 +33,12 @@ const (
 	ErrAuthOtherAccount       = "endpoints.auth_other_account"
 	ErrFormFileMustBeProvided = "endpoints.form_file_must_be_provided"
 	ErrQueryParamRequired     = "endpoints.query_param_required"
+	ErrInvalidQueryParam      = "endpoints.invalid_query_param"
 	ErrPathParamRequired      = "endpoints.path_param_required"
 	ErrAnyQueryParamRequired  = "endpoints.any_query_param_required"
 	ErrAnyPathParamRequired   = "endpoints.any_path_param_required"
 	ErrLogsJobIDRequired      = "endpoints.logs_job_id_required"
+	ErrEndpointTokenInvalid   = "endpoints.endpoint_token_invalid"
 )
 
 func ErrorAPIVersionMismatch(operatorVersion string, clientVersion string) error {
@@ -81,6 +83,13 @@ func ErrorAuthOtherAccount() error {
 	})
 }
 
+func ErrorEndpointTokenInvalid(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEndpointTokenInvalid,
+		Message: fmt.Sprintf("invalid token for %s; tokens can be created by running `cortex token create %s`", apiName, apiName),
+	})
+}
+
 func ErrorFormFileMustBeProvided(fileName string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrFormFileMustBeProvided,
@@ -94,6 +103,13 @@ func ErrorQueryParamRequired(param string) error {
 	})
 }
 
+func ErrorInvalidQueryParam(param string, value string, hint string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidQueryParam,
+		Message: fmt.Sprintf("invalid value for query param %s: %s (%s)", param, s.UserStr(value), hint),
+	})
+}
+
 func ErrorPathParamRequired(param string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrPathParamRequired,
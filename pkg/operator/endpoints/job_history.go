@@ -0,0 +1,105 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/gorilla/mux"
+)
+
+func GetJobHistory(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if deployedResource.Kind != userconfig.BatchAPIKind {
+		respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.BatchAPIKind))
+		return
+	}
+
+	query, err := getJobHistoryQuery(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	jobHistory, err := batchapi.GetJobHistory(apiName, *query)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, jobHistory)
+}
+
+func getJobHistoryQuery(r *http.Request) (*schema.JobHistoryQuery, error) {
+	query := schema.JobHistoryQuery{
+		Limit: getOptionalIntQParam("limit", batchapi.DefaultJobHistoryLimit, r),
+		After: getOptionalQParam("after", r),
+	}
+	if query.Limit < 1 || query.Limit > batchapi.MaxJobHistoryLimit {
+		return nil, ErrorInvalidQueryParam("limit", s.Int(query.Limit), "must be between 1 and "+s.Int(batchapi.MaxJobHistoryLimit))
+	}
+
+	if statusStr := getOptionalQParam("status", r); statusStr != "" {
+		var jobCode status.JobCode
+		jobCode.UnmarshalText([]byte(s.EnsurePrefix(statusStr, "status_")))
+		if jobCode == status.JobUnknown {
+			return nil, ErrorInvalidQueryParam("status", statusStr, "must be a job status (e.g. succeeded)")
+		}
+		query.Status = &jobCode
+	}
+
+	for _, param := range []string{"submitted_after", "submitted_before"} {
+		timeStr := getOptionalQParam(param, r)
+		if timeStr == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return nil, ErrorInvalidQueryParam(param, timeStr, "must be an RFC 3339 timestamp (e.g. 2020-12-31T23:59:59Z)")
+		}
+		if param == "submitted_after" {
+			query.SubmittedAfter = &t
+		} else {
+			query.SubmittedBefore = &t
+		}
+	}
+
+	// either "key" or "key=value"
+	if label := getOptionalQParam("label", r); label != "" {
+		split := strings.SplitN(label, "=", 2)
+		query.LabelKey = split[0]
+		if len(split) == 2 {
+			query.LabelValue = split[1]
+		}
+	}
+
+	return &query, nil
+}
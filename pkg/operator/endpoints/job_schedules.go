@@ -0,0 +1,101 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+)
+
+func CreateJobSchedule(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	// max payload size, same as job submissions
+	rw := http.MaxBytesReader(w, r.Body, 10<<20)
+
+	bodyBytes, err := ioutil.ReadAll(rw)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	jobSchedule := schema.JobSchedule{}
+	if err := json.Unmarshal(bodyBytes, &jobSchedule); err != nil {
+		respondError(w, r, errors.Wrap(err, "job schedule"))
+		return
+	}
+
+	response, err := batchapi.CreateJobSchedule(apiName, jobSchedule)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, response)
+}
+
+func GetJobSchedules(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	jobSchedules, err := batchapi.GetJobSchedules(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.GetJobSchedulesResponse{
+		APIName:   apiName,
+		Schedules: jobSchedules,
+	})
+}
+
+func DeleteJobSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiName := vars["apiName"]
+	scheduleName := vars["scheduleName"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if err := batchapi.DeleteJobSchedule(apiName, scheduleName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.DeleteResponse{
+		Message: fmt.Sprintf("deleted job schedule %s", scheduleName),
+	})
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+)
+
+func ListJobs(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	limit := getOptionalIntQParam("limit", batchapi.DefaultJobsLimit, r)
+	if limit < 1 {
+		limit = batchapi.DefaultJobsLimit
+	}
+
+	jobStatuses, err := batchapi.ListJobs(apiName, limit)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.ListJobsResponse{
+		APIName:     apiName,
+		JobStatuses: jobStatuses,
+	})
+}
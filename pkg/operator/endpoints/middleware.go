@@ -26,6 +26,9 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/gorilla/mux"
 )
 
 var _cachedClientIDs = strset.New()
@@ -35,6 +38,7 @@ type ctxKey int
 const (
 	ctxKeyUnknown ctxKey = iota
 	ctxKeyClient
+	ctxKeyCaller
 )
 
 func PanicMiddleware(next http.Handler) http.Handler {
@@ -72,45 +76,128 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		if len(authHeader) < 10 || !strings.HasPrefix(authHeader, "CortexAWS") {
-			respondError(w, r, ErrorHeaderMalformed("Authorization"))
+		if code, err := checkAWSAuthHeader(authHeader); err != nil {
+			respondErrorCode(w, r, code, err)
 			return
 		}
 
-		parts := strings.Split(authHeader[10:], "|")
-		if len(parts) != 2 {
-			respondError(w, r, ErrorHeaderMalformed("Authorization"))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BatchEndpointAuthMiddleware authenticates requests to Batch APIs which have endpoint_auth set to "token". Either one of the API's
+// tokens ("Authorization: Bearer <token>") or the CLI's credentials can be used, so the CLI can manage jobs for any API.
+func BatchEndpointAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiName := mux.Vars(r)["apiName"]
+
+		endpointAuthType, err := batchapi.GetEndpointAuthType(apiName)
+		if err != nil {
+			respondError(w, r, err)
 			return
 		}
 
-		accessKeyID, secretAccessKey := parts[0], parts[1]
-		awsClient, err := aws.NewFromCreds(*config.Cluster.Region, accessKeyID, secretAccessKey)
-		if err != nil {
-			respondError(w, r, ErrorAuthAPIError())
+		if endpointAuthType != userconfig.TokenEndpointAuthType {
+			next.ServeHTTP(w, withCaller(r, "unauthenticated client at "+remoteAddr(r)))
 			return
 		}
 
-		accountID, _, err := awsClient.CheckCredentials()
-		if err != nil {
-			respondErrorCode(w, r, http.StatusForbidden, ErrorAuthInvalid())
+		authHeader := r.Header.Get("Authorization")
+
+		if authHeader == "" {
+			respondErrorCode(w, r, http.StatusUnauthorized, ErrorHeaderMissing("Authorization"))
+			return
+		}
+
+		if strings.HasPrefix(authHeader, "CortexAWS") {
+			if code, err := checkAWSAuthHeader(authHeader); err != nil {
+				respondErrorCode(w, r, code, err)
+				return
+			}
+			next.ServeHTTP(w, withCaller(r, "aws access key "+awsAccessKeyID(authHeader)))
+			return
+		}
+
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			respondErrorCode(w, r, http.StatusUnauthorized, ErrorHeaderMalformed("Authorization"))
 			return
 		}
 
-		operatorAccountID, _, err := config.AWS.GetCachedAccountID()
+		token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		isValid, err := batchapi.IsValidEndpointToken(apiName, token)
 		if err != nil {
-			respondError(w, r, ErrorAuthAPIError())
+			respondError(w, r, err)
 			return
 		}
 
-		if accountID != operatorAccountID {
-			respondErrorCode(w, r, http.StatusForbidden, ErrorAuthOtherAccount())
+		if !isValid {
+			respondErrorCode(w, r, http.StatusForbidden, ErrorEndpointTokenInvalid(apiName))
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, withCaller(r, "endpoint token "+batchapi.EndpointTokenID(token)))
 	})
 }
 
+// the caller is only recorded for requests which have been authenticated by BatchEndpointAuthMiddleware, so that it can be written to the job's logs
+func withCaller(r *http.Request, caller string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), ctxKeyCaller, caller))
+}
+
+func getCaller(r *http.Request) string {
+	if caller, ok := r.Context().Value(ctxKeyCaller).(string); ok {
+		return caller
+	}
+	return "unknown caller"
+}
+
+// requests to batch apis are forwarded by the api load balancer, which sets X-Forwarded-For to the client's address
+func remoteAddr(r *http.Request) string {
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		return strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+	return r.RemoteAddr
+}
+
+// must only be called on headers which have been validated by checkAWSAuthHeader
+func awsAccessKeyID(authHeader string) string {
+	return strings.Split(authHeader[10:], "|")[0]
+}
+
+// Returns the status code to respond with if the header doesn't contain valid AWS credentials for the cluster's account
+func checkAWSAuthHeader(authHeader string) (int, error) {
+	if len(authHeader) < 10 || !strings.HasPrefix(authHeader, "CortexAWS") {
+		return http.StatusBadRequest, ErrorHeaderMalformed("Authorization")
+	}
+
+	parts := strings.Split(authHeader[10:], "|")
+	if len(parts) != 2 {
+		return http.StatusBadRequest, ErrorHeaderMalformed("Authorization")
+	}
+
+	accessKeyID, secretAccessKey := parts[0], parts[1]
+	awsClient, err := aws.NewFromCreds(*config.Cluster.Region, accessKeyID, secretAccessKey)
+	if err != nil {
+		return http.StatusBadRequest, ErrorAuthAPIError()
+	}
+
+	accountID, _, err := awsClient.CheckCredentials()
+	if err != nil {
+		return http.StatusForbidden, ErrorAuthInvalid()
+	}
+
+	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
+	if err != nil {
+		return http.StatusBadRequest, ErrorAuthAPIError()
+	}
+
+	if accountID != operatorAccountID {
+		return http.StatusForbidden, ErrorAuthOtherAccount()
+	}
+
+	return http.StatusOK, nil
+}
+
 func APIVersionCheckMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/info" {
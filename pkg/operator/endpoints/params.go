@@ -51,3 +51,12 @@ func getOptionalBoolQParam(paramName string, defaultVal bool, r *http.Request) b
 	}
 	return defaultVal
 }
+
+func getOptionalIntQParam(paramName string, defaultVal int, r *http.Request) int {
+	param := r.URL.Query().Get(paramName)
+	paramInt, ok := s.ParseInt(param)
+	if ok {
+		return paramInt
+	}
+	return defaultVal
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/gorilla/mux"
+)
+
+func PauseJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiName := vars["apiName"]
+	jobID := vars["jobID"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	err := batchapi.PauseJob(spec.JobKey{APIName: apiName, ID: jobID})
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.PauseJobResponse{
+		Message: fmt.Sprintf("paused job %s", jobID),
+	})
+}
+
+func ResumeJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiName := vars["apiName"]
+	jobID := vars["jobID"]
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var workers *int
+	if workersStr := getOptionalQParam("workers", r); workersStr != "" {
+		workersInt, ok := s.ParseInt(workersStr)
+		if !ok {
+			respondError(w, r, ErrorInvalidQueryParam("workers", workersStr, "must be an integer"))
+			return
+		}
+		workers = &workersInt
+	}
+
+	jobSpec, err := batchapi.ResumeJob(spec.JobKey{APIName: apiName, ID: jobID}, workers, getCaller(r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.ResumeJobResponse{
+		Message: fmt.Sprintf("resumed job %s with %d %s", jobID, jobSpec.Workers, s.PluralS("worker", jobSpec.Workers)),
+	})
+}
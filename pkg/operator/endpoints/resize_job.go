@@ -0,0 +1,61 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/gorilla/mux"
+)
+
+func ResizeJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiName := vars["apiName"]
+	jobID := vars["jobID"]
+
+	workersStr, err := getRequiredQueryParam("workers", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	workers, ok := s.ParseInt(workersStr)
+	if !ok {
+		respondError(w, r, ErrorInvalidQueryParam("workers", workersStr, "must be an integer"))
+		return
+	}
+
+	if err := validateBatchAPIIsDeployed(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	jobSpec, err := batchapi.ResizeJob(spec.JobKey{APIName: apiName, ID: jobID}, workers, getCaller(r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.ResizeJobResponse{
+		Message: fmt.Sprintf("job %s has %d %s", jobID, jobSpec.Workers, s.PluralS("worker", jobSpec.Workers)),
+	})
+}
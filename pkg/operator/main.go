@@ -43,6 +43,10 @@ func main() {
 
 	telemetry.Event("operator.init")
 
+	if err := batchapi.InitJobStore(); err != nil {
+		exit.Error(errors.Wrap(err, "init"))
+	}
+
 	_, err := operator.UpdateMemoryCapacityConfigMap()
 	if err != nil {
 		exit.Error(errors.Wrap(err, "init"))
@@ -64,16 +68,29 @@ func main() {
 	cron.Run(operator.DeleteEvictedPods, operator.ErrorHandler("delete evicted pods"), 12*time.Hour)
 	cron.Run(operator.InstanceTelemetry, operator.ErrorHandler("instance telemetry"), 1*time.Hour)
 	cron.Run(batchapi.ManageJobResources, operator.ErrorHandler("manage jobs"), batchapi.ManageJobResourcesCronPeriod)
+	cron.Run(batchapi.RunJobSchedules, operator.ErrorHandler("run job schedules"), batchapi.RunJobSchedulesCronPeriod)
+	cron.Run(batchapi.DeleteExpiredJobs, operator.ErrorHandler("delete expired jobs"), batchapi.DeleteExpiredJobsCronPeriod)
 
 	router := mux.NewRouter()
 
 	routerWithoutAuth := router.NewRoute().Subrouter()
 	routerWithoutAuth.Use(endpoints.PanicMiddleware)
 	routerWithoutAuth.HandleFunc("/verifycortex", endpoints.VerifyCortex).Methods("GET")
-	routerWithoutAuth.HandleFunc("/batch/{apiName}", endpoints.SubmitJob).Methods("POST")
-	routerWithoutAuth.HandleFunc("/batch/{apiName}/{jobID}", endpoints.GetJob).Methods("GET")
-	routerWithoutAuth.HandleFunc("/batch/{apiName}/{jobID}", endpoints.StopJob).Methods("DELETE")
-	routerWithoutAuth.HandleFunc("/logs/{apiName}/{jobID}", endpoints.ReadJobLogs)
+
+	// batch api endpoints only require authentication if the api's endpoint_auth is set
+	routerWithBatchEndpointAuth := router.NewRoute().Subrouter()
+	routerWithBatchEndpointAuth.Use(endpoints.PanicMiddleware)
+	routerWithBatchEndpointAuth.Use(endpoints.BatchEndpointAuthMiddleware)
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}", endpoints.SubmitJob).Methods("POST")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}", endpoints.ListJobs).Methods("GET")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}", endpoints.GetJob).Methods("GET")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}", endpoints.StopJob).Methods("DELETE")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}/pause", endpoints.PauseJob).Methods("POST")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}/resume", endpoints.ResumeJob).Methods("POST")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}/resize", endpoints.ResizeJob).Methods("POST")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}/failed", endpoints.GetFailedBatches).Methods("GET")
+	routerWithBatchEndpointAuth.HandleFunc("/batch/{apiName}/{jobID}/failed/retry", endpoints.RetryFailedBatches).Methods("POST")
+	routerWithBatchEndpointAuth.HandleFunc("/logs/{apiName}/{jobID}", endpoints.ReadJobLogs)
 
 	routerWithAuth := router.NewRoute().Subrouter()
 
@@ -88,8 +105,26 @@ func main() {
 	routerWithAuth.HandleFunc("/delete/{apiName}", endpoints.Delete).Methods("DELETE")
 	routerWithAuth.HandleFunc("/get", endpoints.GetAPIs).Methods("GET")
 	routerWithAuth.HandleFunc("/get/{apiName}", endpoints.GetAPI).Methods("GET")
+	routerWithAuth.HandleFunc("/autoscaling/{apiName}", endpoints.GetAutoscaling).Methods("GET")
+	routerWithAuth.HandleFunc("/history/{apiName}", endpoints.GetJobHistory).Methods("GET")
+	routerWithAuth.HandleFunc("/tokens/{apiName}", endpoints.CreateEndpointToken).Methods("POST")
+	routerWithAuth.HandleFunc("/tokens/{apiName}", endpoints.GetEndpointTokens).Methods("GET")
+	routerWithAuth.HandleFunc("/tokens/{apiName}/{tokenID}", endpoints.DeleteEndpointToken).Methods("DELETE")
+	routerWithAuth.HandleFunc("/schedules/{apiName}", endpoints.CreateJobSchedule).Methods("POST")
+	routerWithAuth.HandleFunc("/schedules/{apiName}", endpoints.GetJobSchedules).Methods("GET")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleName}", endpoints.DeleteJobSchedule).Methods("DELETE")
 	routerWithAuth.HandleFunc("/logs/{apiName}", endpoints.ReadLogs)
 
+	// the activator only receives requests which are routed to it by the virtual services of apis that have been scaled to zero, so it is served on a port which is not exposed by the operator's load balancer
+	activatorRouter := mux.NewRouter()
+	activatorRouter.Use(endpoints.PanicMiddleware)
+	activatorRouter.HandleFunc("/activate/{apiName}", endpoints.Activate)
+
+	go func() {
+		log.Print("Running activator on port " + operator.ActivatorPortStr)
+		log.Fatal(http.ListenAndServe(":"+operator.ActivatorPortStr, activatorRouter))
+	}()
+
 	log.Print("Running on port " + _operatorPortStr)
 	log.Fatal(http.ListenAndServe(":"+_operatorPortStr, router))
 }
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+/*
+CPU Reservations:
+
+FluentD 200
+StatsD 100
+KubeProxy 100
+AWS cni 10
+Reserved (150 + 150) see eks.yaml for details
+*/
+var _cortexCPUReserve = kresource.MustParse("710m")
+
+/*
+Memory Reservations:
+
+FluentD 200
+StatsD 100
+Reserved (300 + 300 + 200) see eks.yaml for details
+*/
+var _cortexMemReserve = kresource.MustParse("1100Mi")
+
+var _nvidiaCPUReserve = kresource.MustParse("100m")
+var _nvidiaMemReserve = kresource.MustParse("100Mi")
+
+var _inferentiaCPUReserve = kresource.MustParse("100m")
+var _inferentiaMemReserve = kresource.MustParse("100Mi")
+
+// InstanceUserCapacity returns the compute of each of the cluster's instances which is available to APIs (maxMem is the memory capacity of an instance, see UpdateMemoryCapacityConfigMap)
+func InstanceUserCapacity(maxMem kresource.Quantity) userconfig.Compute {
+	maxMem.Sub(_cortexMemReserve)
+
+	maxCPU := config.Cluster.InstanceMetadata.CPU
+	maxCPU.Sub(_cortexCPUReserve)
+
+	maxGPU := config.Cluster.InstanceMetadata.GPU
+	if maxGPU > 0 {
+		// Reserve resources for nvidia device plugin daemonset
+		maxCPU.Sub(_nvidiaCPUReserve)
+		maxMem.Sub(_nvidiaMemReserve)
+	}
+
+	maxInf := config.Cluster.InstanceMetadata.Inf
+	if maxInf > 0 {
+		// Reserve resources for inferentia device plugin daemonset
+		maxCPU.Sub(_inferentiaCPUReserve)
+		maxMem.Sub(_inferentiaMemReserve)
+	}
+
+	return userconfig.Compute{
+		CPU: k8s.WrapQuantity(maxCPU),
+		Mem: k8s.WrapQuantity(maxMem),
+		GPU: maxGPU,
+		Inf: maxInf,
+	}
+}
@@ -43,9 +43,13 @@ import (
 )
 
 const (
-	DefaultPortInt32 = int32(8888)
-	DefaultPortStr   = "8888"
-	APIContainerName = "api"
+	DefaultPortInt32        = int32(8888)
+	DefaultPortStr          = "8888"
+	RequestMonitorPortInt32 = int32(8889)
+	RequestMonitorPortStr   = "8889"
+	ActivatorPortInt32      = int32(8890)
+	ActivatorPortStr        = "8890"
+	APIContainerName        = "api"
 )
 
 const (
@@ -66,6 +70,7 @@ const (
 	_neuronRTDSocket                               = "/sock/neuron.sock"
 	_apiLivenessStalePeriod                        = 7 // seconds (there is a 2-second buffer to be safe)
 	_requestMonitorReadinessFile                   = "/request_monitor_ready.txt"
+	_requestMonitorSocket                          = "/mnt/request_monitor.sock"
 )
 
 var (
@@ -393,6 +398,10 @@ func getEnvVars(api *spec.API, container string) []kcore.EnvVar {
 					Name:  "CORTEX_API_SPEC",
 					Value: aws.S3Path(config.Cluster.Bucket, api.PredictorKey),
 				},
+				kcore.EnvVar{
+					Name:  "CORTEX_REQUEST_MONITOR_SOCKET",
+					Value: _requestMonitorSocket,
+				},
 			)
 		} else {
 			envVars = append(envVars,
@@ -738,10 +747,20 @@ func RequestMonitorContainer(api *spec.API) kcore.Container {
 		Name:            "request-monitor",
 		Image:           config.Cluster.ImageRequestMonitor,
 		ImagePullPolicy: kcore.PullAlways,
-		Args:            []string{api.Name, config.Cluster.ClusterName},
-		EnvFrom:         BaseEnvVars,
-		VolumeMounts:    DefaultVolumeMounts,
-		ReadinessProbe:  FileExistsProbe(_requestMonitorReadinessFile),
+		Args: []string{
+			"--api-name=" + api.Name,
+			"--cluster-name=" + config.Cluster.ClusterName,
+			"--port=" + RequestMonitorPortStr,
+			"--sink=cloudwatch",
+			"--mode=socket",
+			"--socket=" + _requestMonitorSocket,
+		},
+		Ports: []kcore.ContainerPort{
+			{ContainerPort: RequestMonitorPortInt32},
+		},
+		EnvFrom:        BaseEnvVars,
+		VolumeMounts:   DefaultVolumeMounts,
+		ReadinessProbe: FileExistsProbe(_requestMonitorReadinessFile),
 		Resources: kcore.ResourceRequirements{
 			Requests: kcore.ResourceList{
 				kcore.ResourceCPU:    _requestMonitorCPURequest,
@@ -36,6 +36,9 @@ import (
 	klabels "k8s.io/apimachinery/pkg/labels"
 )
 
+// DefaultJobsLimit is the number of jobs which are listed for an API if a limit isn't specified
+const DefaultJobsLimit = 10
+
 func UpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, string, error) {
 	prevVirtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiConfig.Name))
 	if err != nil {
@@ -94,6 +97,8 @@ func DeleteAPI(apiName string, keepCache bool) error {
 	// best effort deletion, so don't handle error yet
 	virtualService, vsErr := config.K8s.GetVirtualService(operator.K8sName(apiName))
 
+	deleteCachedEndpointTokens(apiName)
+
 	err := parallel.RunFirstErr(
 		func() error {
 			return vsErr
@@ -157,6 +162,9 @@ func deleteS3Resources(apiName string) error {
 			deleteAllInProgressFilesByAPI(apiName) // not useful xml error is thrown, swallow the error
 			return nil
 		},
+		func() error {
+			return _jobStore.DeleteJobStates(apiName)
+		},
 	)
 }
 
@@ -255,22 +263,38 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 		return nil, err
 	}
 
-	k8sJobs, err := config.K8s.ListJobsByLabel("apiName", deployedResource.Name)
+	endpoint, err := operator.APIEndpoint(api)
 	if err != nil {
 		return nil, err
 	}
 
-	jobIDToK8sJobMap := map[string]*kbatch.Job{}
-	for _, job := range k8sJobs {
-		jobIDToK8sJobMap[job.Labels["jobID"]] = &job
+	jobStatuses, err := ListJobs(deployedResource.Name, DefaultJobsLimit)
+	if err != nil {
+		return nil, err
 	}
 
-	endpoint, err := operator.APIEndpoint(api)
+	return &schema.GetAPIResponse{
+		BatchAPI: &schema.BatchAPI{
+			Spec:        *api,
+			JobStatuses: jobStatuses,
+			Endpoint:    endpoint,
+		},
+	}, nil
+}
+
+// ListJobs returns the statuses of all of the API's in-progress jobs, followed by its most recently submitted jobs until limit jobs have been listed
+func ListJobs(apiName string, limit int) ([]status.JobStatus, error) {
+	k8sJobs, err := config.K8s.ListJobsByLabel("apiName", apiName)
 	if err != nil {
 		return nil, err
 	}
 
-	pods, err := config.K8s.ListPodsByLabel("apiName", deployedResource.Name)
+	jobIDToK8sJobMap := map[string]*kbatch.Job{}
+	for i := range k8sJobs {
+		jobIDToK8sJobMap[k8sJobs[i].Labels["jobID"]] = &k8sJobs[i]
+	}
+
+	pods, err := config.K8s.ListPodsByLabel("apiName", apiName)
 	if err != nil {
 		return nil, err
 	}
@@ -280,7 +304,7 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 		jobIDToPodsMap[pod.Labels["jobID"]] = append(jobIDToPodsMap[pod.Labels["jobID"]], pod)
 	}
 
-	inProgressJobKeys, err := listAllInProgressJobKeysByAPI(deployedResource.Name)
+	inProgressJobKeys, err := listAllInProgressJobKeysByAPI(apiName)
 	if err != nil {
 		return nil, err
 	}
@@ -297,8 +321,8 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 		jobIDSet.Add(jobKey.ID)
 	}
 
-	if len(jobStatuses) < 10 {
-		jobStates, err := getMostRecentlySubmittedJobStates(deployedResource.Name, 10+len(jobStatuses))
+	if len(jobStatuses) < limit {
+		jobStates, err := getMostRecentlySubmittedJobStates(apiName, limit+len(jobStatuses))
 		if err != nil {
 			return nil, err
 		}
@@ -314,17 +338,11 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 			}
 
 			jobStatuses = append(jobStatuses, *jobStatus)
-			if len(jobStatuses) == 10 {
+			if len(jobStatuses) == limit {
 				break
 			}
 		}
 	}
 
-	return &schema.GetAPIResponse{
-		BatchAPI: &schema.BatchAPI{
-			Spec:        *api,
-			JobStatuses: jobStatuses,
-			Endpoint:    endpoint,
-		},
-	}, nil
+	return jobStatuses, nil
 }
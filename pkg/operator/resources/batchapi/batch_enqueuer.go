@@ -17,41 +17,51 @@ limitations under the License.
 package batchapi
 
 import (
+	"encoding/json"
 	"fmt"
+	"path"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
 const (
 	_messageSizeLimit    = 256 * 1024
 	_maxMessagesPerBatch = 10
+	// the message attribute which holds the key of a batch that was stored in the cluster's bucket (the worker reads the batch from s3)
+	_spilledBatchAttribute = "spilled_batch"
 )
 
 type sqsBatchUploader struct {
 	queueURL             string
+	jobKey               spec.JobKey
 	retries              int // default 3 times
 	messageList          []*sqs.SendMessageBatchRequestEntry
 	messageIDToListIndex map[string]int
 	totalBytes           int
 	TotalBatches         int
+	SpilledBatches       int
 }
 
-func newSQSBatchUploader(queueURL string) *sqsBatchUploader {
+func newSQSBatchUploader(queueURL string, jobKey spec.JobKey) *sqsBatchUploader {
 	return &sqsBatchUploader{
 		queueURL:             queueURL,
+		jobKey:               jobKey,
 		retries:              3,
 		messageIDToListIndex: map[string]int{},
 	}
 }
 
-func (uploader *sqsBatchUploader) AddToBatch(id string, body *string) error {
-	if len(*body) > _messageSizeLimit {
-		return ErrorMessageExceedsMaxSize(len(*body), _messageSizeLimit)
-	}
+// spilled batches are stored outside of the job's prefix (which only contains the job's spec and status files), and are deleted along with the job's runtime resources
+func spilledBatchesPrefix(jobKey spec.JobKey) string {
+	return path.Join("apis", jobKey.APIName, "spilled_batches", jobKey.ID) + "/"
+}
 
+func (uploader *sqsBatchUploader) AddToBatch(id string, body *string) error {
 	message := &sqs.SendMessageBatchRequestEntry{
 		Id:                     aws.String(id),
 		MessageBody:            body,
@@ -59,7 +69,15 @@ func (uploader *sqsBatchUploader) AddToBatch(id string, body *string) error {
 		MessageGroupId:         aws.String(id), // aws recommends message group id per message to improve chances of exactly-once
 	}
 
-	if len(*message.MessageBody)+uploader.totalBytes > _messageSizeLimit || len(uploader.messageList) == _maxMessagesPerBatch {
+	if len(*body) > _messageSizeLimit {
+		err := uploader.spillBatch(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	messageSize := sqsMessageSize(message)
+	if messageSize+uploader.totalBytes > _messageSizeLimit || len(uploader.messageList) == _maxMessagesPerBatch {
 		err := uploader.Flush()
 		if err != nil {
 			return err
@@ -68,11 +86,48 @@ func (uploader *sqsBatchUploader) AddToBatch(id string, body *string) error {
 
 	uploader.messageList = append(uploader.messageList, message)
 	uploader.messageIDToListIndex[id] = uploader.TotalBatches
-	uploader.totalBytes += len(*message.MessageBody)
+	uploader.totalBytes += messageSize
 	uploader.TotalBatches++
 	return nil
 }
 
+// spillBatch stores a batch which exceeds the sqs message size limit in the cluster's bucket, and replaces the message's body with the batch's s3 path
+func (uploader *sqsBatchUploader) spillBatch(message *sqs.SendMessageBatchRequestEntry) error {
+	key := path.Join(spilledBatchesPrefix(uploader.jobKey), *message.Id+".json")
+	err := config.AWS.UploadStringToS3(*message.MessageBody, config.Cluster.Bucket, key)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to store batch %d in s3", uploader.TotalBatches))
+	}
+
+	if uploader.SpilledBatches == 0 {
+		writeToJobLogStream(uploader.jobKey, fmt.Sprintf("batch %d has a size of %d bytes which exceeds the sqs message size limit (%d bytes), so it will be stored in s3 (as will any other batches which exceed the limit)", uploader.TotalBatches, len(*message.MessageBody), _messageSizeLimit))
+	}
+	uploader.SpilledBatches++
+
+	s3PathJSON, err := json.Marshal(awslib.S3Path(config.Cluster.Bucket, key))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	message.MessageBody = aws.String(string(s3PathJSON))
+	message.MessageAttributes = map[string]*sqs.MessageAttributeValue{
+		_spilledBatchAttribute: {
+			DataType:    aws.String("String"),
+			StringValue: aws.String(key),
+		},
+	}
+	return nil
+}
+
+// message attributes count towards the size limit
+func sqsMessageSize(message *sqs.SendMessageBatchRequestEntry) int {
+	size := len(*message.MessageBody)
+	for name, attribute := range message.MessageAttributes {
+		size += len(name) + len(*attribute.DataType) + len(*attribute.StringValue)
+	}
+	return size
+}
+
 func (uploader *sqsBatchUploader) Flush() error {
 	if len(uploader.messageList) == 0 {
 		return nil
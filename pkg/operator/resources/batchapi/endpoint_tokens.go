@@ -0,0 +1,214 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
+	"github.com/cortexlabs/cortex/pkg/lib/random"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const (
+	_endpointTokenIDLength     = 8
+	_endpointTokenSecretLength = 32 // bytes
+	_endpointTokenSeparator    = "."
+)
+
+// the tokens are only modified by the operator, so they can be cached until they are changed
+var (
+	_endpointTokensCache = make(map[string][]storedEndpointToken) // apiName -> tokens
+	_endpointTokensMutex = sync.Mutex{}
+)
+
+// only a hash of each token's secret is stored, so the token can't be recovered from the cluster's bucket
+type storedEndpointToken struct {
+	schema.EndpointToken
+	HashedSecret string `json:"hashed_secret"`
+}
+
+// the tokens are kept when the API is updated, and deleted along with the API's other files in the cluster's bucket
+func endpointTokensKey(apiName string) string {
+	return filepath.Join("apis", apiName, "auth", "endpoint_tokens.json")
+}
+
+// must be called with _endpointTokensMutex held
+func getStoredEndpointTokens(apiName string) ([]storedEndpointToken, error) {
+	if tokens, ok := _endpointTokensCache[apiName]; ok {
+		return tokens, nil
+	}
+
+	key := endpointTokensKey(apiName)
+
+	tokens := []storedEndpointToken{}
+	exists, err := config.AWS.IsS3File(config.Cluster.Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if err := config.AWS.ReadJSONFromS3(&tokens, config.Cluster.Bucket, key); err != nil {
+			return nil, err
+		}
+	}
+
+	_endpointTokensCache[apiName] = tokens
+	return tokens, nil
+}
+
+// must be called with _endpointTokensMutex held
+func saveStoredEndpointTokens(apiName string, tokens []storedEndpointToken) error {
+	delete(_endpointTokensCache, apiName)
+	return config.AWS.UploadJSONToS3(tokens, config.Cluster.Bucket, endpointTokensKey(apiName))
+}
+
+func deleteCachedEndpointTokens(apiName string) {
+	_endpointTokensMutex.Lock()
+	defer _endpointTokensMutex.Unlock()
+	delete(_endpointTokensCache, apiName)
+}
+
+// Returns the new token, which is the only time that the token's secret is available
+func CreateEndpointToken(apiName string, description string) (*schema.CreateEndpointTokenResponse, error) {
+	secretBytes := make([]byte, _endpointTokenSecretLength)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	endpointToken := schema.EndpointToken{
+		ID:          random.LowercaseString(_endpointTokenIDLength),
+		Description: description,
+		CreatedTime: time.Now(),
+	}
+
+	_endpointTokensMutex.Lock()
+	defer _endpointTokensMutex.Unlock()
+
+	tokens, err := getStoredEndpointTokens(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens = append(tokens, storedEndpointToken{
+		EndpointToken: endpointToken,
+		HashedSecret:  hash.String(secret),
+	})
+
+	if err := saveStoredEndpointTokens(apiName, tokens); err != nil {
+		return nil, err
+	}
+
+	return &schema.CreateEndpointTokenResponse{
+		EndpointToken: endpointToken,
+		Token:         endpointToken.ID + _endpointTokenSeparator + secret,
+	}, nil
+}
+
+func GetEndpointTokens(apiName string) ([]schema.EndpointToken, error) {
+	_endpointTokensMutex.Lock()
+	defer _endpointTokensMutex.Unlock()
+
+	tokens, err := getStoredEndpointTokens(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointTokens := make([]schema.EndpointToken, len(tokens))
+	for i := range tokens {
+		endpointTokens[i] = tokens[i].EndpointToken
+	}
+
+	return endpointTokens, nil
+}
+
+func DeleteEndpointToken(apiName string, tokenID string) error {
+	_endpointTokensMutex.Lock()
+	defer _endpointTokensMutex.Unlock()
+
+	tokens, err := getStoredEndpointTokens(apiName)
+	if err != nil {
+		return err
+	}
+
+	remainingTokens := make([]storedEndpointToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.ID != tokenID {
+			remainingTokens = append(remainingTokens, token)
+		}
+	}
+
+	if len(remainingTokens) == len(tokens) {
+		return ErrorEndpointTokenNotFound(apiName, tokenID)
+	}
+
+	return saveStoredEndpointTokens(apiName, remainingTokens)
+}
+
+// IsValidEndpointToken returns whether the token (as returned by CreateEndpointToken) is one of the API's tokens
+func IsValidEndpointToken(apiName string, token string) (bool, error) {
+	parts := strings.SplitN(token, _endpointTokenSeparator, 2)
+	if len(parts) != 2 {
+		return false, nil
+	}
+	tokenID, hashedSecret := parts[0], hash.String(parts[1])
+
+	_endpointTokensMutex.Lock()
+	defer _endpointTokensMutex.Unlock()
+
+	tokens, err := getStoredEndpointTokens(apiName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, storedToken := range tokens {
+		if storedToken.ID == tokenID {
+			return subtle.ConstantTimeCompare([]byte(storedToken.HashedSecret), []byte(hashedSecret)) == 1, nil
+		}
+	}
+
+	return false, nil
+}
+
+// EndpointTokenID returns the ID of a token (as returned by CreateEndpointToken), which can be used to identify the token's holder without revealing its secret
+func EndpointTokenID(token string) string {
+	return strings.SplitN(token, _endpointTokenSeparator, 2)[0]
+}
+
+// Returns NoneEndpointAuthType if the API isn't deployed (in which case the request will fail regardless)
+func GetEndpointAuthType(apiName string) (userconfig.EndpointAuthType, error) {
+	virtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
+	if err != nil {
+		return userconfig.UnknownEndpointAuthType, err
+	}
+
+	if virtualService == nil || userconfig.KindFromString(virtualService.Labels["apiKind"]) != userconfig.BatchAPIKind {
+		return userconfig.NoneEndpointAuthType, nil
+	}
+
+	return userconfig.EndpointAuthFromAnnotations(virtualService)
+}
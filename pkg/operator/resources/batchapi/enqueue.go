@@ -18,11 +18,13 @@ package batchapi
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
-	"path"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -32,6 +34,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/random"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
@@ -42,6 +45,8 @@ const (
 	_enqueuingLivenessFile   = "enqueuing_liveness"
 	_enqueuingLivenessPeriod = 20 * time.Second
 	_s3DownloadChunkSize     = 32 * 1024 * 1024
+	_defaultCSVDelimiter     = ","
+	_utf8ByteOrderMark       = "\ufeff"
 )
 
 func randomMessageID() string {
@@ -49,8 +54,7 @@ func randomMessageID() string {
 }
 
 func updateLiveness(jobKey spec.JobKey) error {
-	s3Key := path.Join(jobKey.Prefix(), _enqueuingLivenessFile)
-	err := config.AWS.UploadJSONToS3(time.Now(), config.Cluster.Bucket, s3Key)
+	err := _jobStore.UpdateLiveness(jobKey)
 	if err != nil {
 		return errors.Wrap(err, "failed to update liveness", jobKey.UserString())
 	}
@@ -82,6 +86,11 @@ func enqueue(jobSpec *spec.Job, submission *schema.JobSubmission) (int, error) {
 		if err != nil {
 			return 0, err
 		}
+	} else if submission.CSVFiles != nil {
+		totalBatches, err = enqueueS3CSVContents(jobSpec, submission.CSVFiles)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	randomMessageID := randomMessageID()
@@ -112,7 +121,7 @@ func enqueueItems(jobSpec *spec.Job, itemList *schema.ItemList) (int, error) {
 
 	writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("partitioning %d items found in job submission into %d batches of size %d", len(itemList.Items), batchCount, itemList.BatchSize))
 
-	uploader := newSQSBatchUploader(jobSpec.SQSUrl)
+	uploader := newSQSBatchUploader(jobSpec.SQSUrl, jobSpec.JobKey)
 
 	for i := 0; i < batchCount; i++ {
 		min := i * (itemList.BatchSize)
@@ -151,7 +160,7 @@ func enqueueItems(jobSpec *spec.Job, itemList *schema.ItemList) (int, error) {
 
 func enqueueS3Paths(jobSpec *spec.Job, s3PathsLister *schema.FilePathLister) (int, error) {
 	var s3PathList []string
-	uploader := newSQSBatchUploader(jobSpec.SQSUrl)
+	uploader := newSQSBatchUploader(jobSpec.SQSUrl, jobSpec.JobKey)
 
 	err := s3IteratorFromLister(s3PathsLister.S3Lister, func(bucket string, s3Obj *s3.Object) (bool, error) {
 		s3Path := awslib.S3Path(bucket, *s3Obj.Key)
@@ -229,7 +238,7 @@ func (j *jsonBuffer) Length() int {
 
 func enqueueS3FileContents(jobSpec *spec.Job, delimitedFiles *schema.DelimitedFiles) (int, error) {
 	jsonMessageList := newJSONBuffer(delimitedFiles.BatchSize)
-	uploader := newSQSBatchUploader(jobSpec.SQSUrl)
+	uploader := newSQSBatchUploader(jobSpec.SQSUrl, jobSpec.JobKey)
 
 	bytesBuffer := bytes.NewBuffer([]byte{})
 	err := s3IteratorFromLister(delimitedFiles.S3Lister, func(bucket string, s3Obj *s3.Object) (bool, error) {
@@ -291,24 +300,153 @@ func streamJSONToQueue(jobSpec *spec.Job, uploader *sqsBatchUploader, bytesBuffe
 		}
 
 		if len(doc) > _messageSizeLimit {
-			return errors.Wrap(ErrorMessageExceedsMaxSize(len(doc), _messageSizeLimit), fmt.Sprintf("item %d", *itemIndex))
+			return ErrorItemSizeExceedsLimit(*itemIndex, len(doc), _messageSizeLimit)
 		}
 		*itemIndex++
-		jsonMessageList.Add(doc)
-		if jsonMessageList.Length() == jsonMessageList.BatchSize {
-			err := addJSONObjectsToQueue(uploader, jsonMessageList)
+
+		err = addJSONObjectToBatch(jobSpec, uploader, jsonMessageList, doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// adds the item to the current batch, and enqueues the batch once it is full
+func addJSONObjectToBatch(jobSpec *spec.Job, uploader *sqsBatchUploader, jsonMessageList *jsonBuffer, doc json.RawMessage) error {
+	jsonMessageList.Add(doc)
+	if jsonMessageList.Length() == jsonMessageList.BatchSize {
+		err := addJSONObjectsToQueue(uploader, jsonMessageList)
+		if err != nil {
+			return err
+		}
+		jsonMessageList.Clear()
+
+		if uploader.TotalBatches%100 == 0 {
+			writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("enqueued %d batches", uploader.TotalBatches))
+		}
+	}
+
+	return nil
+}
+
+func enqueueS3CSVContents(jobSpec *spec.Job, csvFiles *schema.CSVFiles) (int, error) {
+	jsonMessageList := newJSONBuffer(csvFiles.BatchSize)
+	uploader := newSQSBatchUploader(jobSpec.SQSUrl, jobSpec.JobKey)
+
+	itemIndex := 0
+	err := s3IteratorFromLister(csvFiles.S3Lister, func(bucket string, s3Obj *s3.Object) (bool, error) {
+		s3Path := awslib.S3Path(bucket, *s3Obj.Key)
+		writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("enqueuing rows from file %s", s3Path))
+
+		fileReader := s3FileReader(bucket, s3Obj)
+		err := streamCSVToQueue(jobSpec, uploader, fileReader, csvFiles, jsonMessageList, &itemIndex)
+		fileReader.Close() // stops the download if the file wasn't read to the end
+		if err != nil {
+			return false, errors.Wrap(err, s3Path)
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if jsonMessageList.Length() != 0 {
+		err := addJSONObjectsToQueue(uploader, jsonMessageList)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = uploader.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	return uploader.TotalBatches, nil
+}
+
+// s3FileReader streams the contents of the S3 file, which is downloaded in chunks; unlike newline delimited json, a csv row may span multiple lines, so the chunks are read as a single stream
+func s3FileReader(bucket string, s3Obj *s3.Object) *io.PipeReader {
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		err := config.AWS.S3FileIterator(bucket, s3Obj, _s3DownloadChunkSize, func(readCloser io.ReadCloser, isLastChunk bool) (bool, error) {
+			defer readCloser.Close()
+			_, err := io.Copy(pipeWriter, readCloser)
 			if err != nil {
-				return err
+				return false, err
 			}
-			jsonMessageList.Clear()
+			return true, nil
+		})
+		pipeWriter.CloseWithError(err) // the reader receives io.EOF if err is nil
+	}()
 
-			if uploader.TotalBatches%100 == 0 {
-				writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("enqueued %d batches", uploader.TotalBatches))
-			}
+	return pipeReader
+}
+
+// itemIndex is the index of the next item across all of the job's files
+func streamCSVToQueue(jobSpec *spec.Job, uploader *sqsBatchUploader, reader io.Reader, csvFiles *schema.CSVFiles, jsonMessageList *jsonBuffer, itemIndex *int) error {
+	return csvItemIterator(reader, csvFiles, func(doc json.RawMessage) error {
+		if len(doc) > _messageSizeLimit {
+			return ErrorItemSizeExceedsLimit(*itemIndex, len(doc), _messageSizeLimit)
+		}
+
+		err := addJSONObjectToBatch(jobSpec, uploader, jsonMessageList, doc)
+		if err != nil {
+			return err
+		}
+		*itemIndex++
+
+		return nil
+	})
+}
+
+// csvItemIterator calls fn with each row of the csv, converted to a json object whose keys are the column names from the header row (all values are strings)
+func csvItemIterator(reader io.Reader, csvFiles *schema.CSVFiles, fn func(doc json.RawMessage) error) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma, _ = utf8.DecodeRuneInString(csvFiles.Delimiter)
+	csvReader.LazyQuotes = csvFiles.LazyQuotes
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	header[0] = strings.TrimPrefix(header[0], _utf8ByteOrderMark)
+	columns := strset.New()
+	for _, column := range header {
+		if columns.Has(column) {
+			return ErrorDuplicateCSVColumn(column)
 		}
+		columns.Add(column)
 	}
 
-	return nil
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err // the number of fields in each row is checked against the header row
+		}
+
+		item := make(map[string]string, len(header))
+		for i, column := range header {
+			item[column] = record[i]
+		}
+
+		doc, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
 }
 
 func addJSONObjectsToQueue(uploader *sqsBatchUploader, jsonMessageList *jsonBuffer) error {
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVItemIterator(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		delimiter  string
+		lazyQuotes bool
+		expected   []string
+		errKind    string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:     "header only",
+			input:    "a,b\n",
+			expected: nil,
+		},
+		{
+			name:     "rows",
+			input:    "a,b\n1,2\n3,4\n",
+			expected: []string{`{"a":"1","b":"2"}`, `{"a":"3","b":"4"}`},
+		},
+		{
+			name:     "no trailing newline",
+			input:    "a,b\n1,2",
+			expected: []string{`{"a":"1","b":"2"}`},
+		},
+		{
+			name:     "byte order mark",
+			input:    _utf8ByteOrderMark + "a,b\n1,2\n",
+			expected: []string{`{"a":"1","b":"2"}`},
+		},
+		{
+			name:     "quoted values",
+			input:    "a,b\n\"1,1\",\"2\n2\"\n",
+			expected: []string{`{"a":"1,1","b":"2\n2"}`},
+		},
+		{
+			name:      "delimiter",
+			input:     "a\tb\n1\t2\n",
+			delimiter: "\t",
+			expected:  []string{`{"a":"1","b":"2"}`},
+		},
+		{
+			name:       "lazy quotes",
+			input:      "a,b\n1\"1,2\n",
+			lazyQuotes: true,
+			expected:   []string{`{"a":"1\"1","b":"2"}`},
+		},
+		{
+			name:    "bare quote",
+			input:   "a,b\n1\"1,2\n",
+			errKind: "csv",
+		},
+		{
+			name:    "wrong number of fields",
+			input:   "a,b\n1,2,3\n",
+			errKind: "csv",
+		},
+		{
+			name:    "duplicate column",
+			input:   "a,a\n1,2\n",
+			errKind: ErrDuplicateCSVColumn,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			csvFiles := &schema.CSVFiles{
+				Delimiter:  ",",
+				LazyQuotes: tc.lazyQuotes,
+			}
+			if tc.delimiter != "" {
+				csvFiles.Delimiter = tc.delimiter
+			}
+
+			var items []string
+			err := csvItemIterator(strings.NewReader(tc.input), csvFiles, func(doc json.RawMessage) error {
+				items = append(items, string(doc))
+				return nil
+			})
+
+			switch tc.errKind {
+			case "":
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, items)
+			case "csv":
+				_, isParseErr := err.(*csv.ParseError)
+				require.True(t, isParseErr, err)
+			default:
+				require.Equal(t, tc.errKind, errors.GetKind(err))
+			}
+		})
+	}
+}
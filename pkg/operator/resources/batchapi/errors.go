@@ -21,7 +21,9 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 )
 
 const (
@@ -31,10 +33,23 @@ const (
 	ErrNoS3FilesFound             = "batchapi.no_s3_files_found"
 	ErrNoDataFoundInJobSubmission = "batchapi.no_data_found_in_job_submission"
 	ErrFailedToEnqueueMessages    = "batchapi.failed_to_enqueue_messages"
-	ErrMessageExceedsMaxSize      = "batchapi.message_exceeds_max_size"
 	ErrConflictingFields          = "batchapi.conflicting_fields"
 	ErrBatchItemSizeExceedsLimit  = "batchapi.item_size_exceeds_limit"
 	ErrSpecifyExactlyOneKey       = "batchapi.specify_exactly_one_key"
+	ErrEndpointTokenNotFound      = "batchapi.endpoint_token_not_found"
+	ErrFailedBatchesNotAvailable  = "batchapi.failed_batches_not_available"
+	ErrNoFailedBatches            = "batchapi.no_failed_batches"
+	ErrJobScheduleNotFound        = "batchapi.job_schedule_not_found"
+	ErrIdempotencyKeyInSchedule   = "batchapi.idempotency_key_in_schedule"
+	ErrUpstreamJobDidNotSucceed   = "batchapi.upstream_job_did_not_succeed"
+	ErrInvalidCSVDelimiter        = "batchapi.invalid_csv_delimiter"
+	ErrDuplicateCSVColumn         = "batchapi.duplicate_csv_column"
+	ErrInvalidJobStatusTransition = "batchapi.invalid_job_status_transition"
+	ErrJobStateConflict           = "batchapi.job_state_conflict"
+	ErrInvalidJobStoreBackend     = "batchapi.invalid_job_store_backend"
+	ErrJobIsNotRunning            = "batchapi.job_is_not_running"
+	ErrJobIsNotPaused             = "batchapi.job_is_not_paused"
+	ErrNotEnoughClusterCapacity   = "batchapi.not_enough_cluster_capacity"
 )
 
 func ErrorJobNotFound(jobKey spec.JobKey) error {
@@ -79,13 +94,6 @@ func ErrorFailedToEnqueueMessages(message string) error {
 	})
 }
 
-func ErrorMessageExceedsMaxSize(messageSize int, messageLimit int) error {
-	return errors.WithStack(&errors.Error{
-		Kind:    ErrMessageExceedsMaxSize,
-		Message: fmt.Sprintf("cannot enqueue message because its size of %d bytes exceeds the %d bytes limit; use a smaller batch size or reduce the size of each of item in the batch", messageSize, messageLimit),
-	})
-}
-
 func ErrorConflictingFields(key string, keys ...string) error {
 	allKeys := append([]string{key}, keys...)
 
@@ -109,3 +117,101 @@ func ErrorSpecifyExactlyOneKey(key string, keys ...string) error {
 		Message: fmt.Sprintf("specify exactly one of the following keys: %s", s.StrsOr(allKeys)),
 	})
 }
+
+func ErrorEndpointTokenNotFound(apiName string, tokenID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEndpointTokenNotFound,
+		Message: fmt.Sprintf("unable to find token %s for api %s", tokenID, apiName),
+	})
+}
+
+func ErrorFailedBatchesNotAvailable(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFailedBatchesNotAvailable,
+		Message: fmt.Sprintf("the failed batches of batch job %s will be available once the job is no longer in progress", jobKey.UserString()),
+	})
+}
+
+func ErrorNoFailedBatches(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoFailedBatches,
+		Message: fmt.Sprintf("batch job %s does not have any failed batches to retry (failed batches are only kept for jobs which were submitted with max_retries)", jobKey.UserString()),
+	})
+}
+
+func ErrorJobScheduleNotFound(apiName string, scheduleName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobScheduleNotFound,
+		Message: fmt.Sprintf("unable to find job schedule %s for api %s", scheduleName, apiName),
+	})
+}
+
+func ErrorIdempotencyKeyInSchedule() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrIdempotencyKeyInSchedule,
+		Message: fmt.Sprintf("%s cannot be set in a job schedule's submission, since every run of the schedule would return the job which was submitted by the first run", schema.IdempotencyKeyKey),
+	})
+}
+
+func ErrorUpstreamJobDidNotSucceed(upstreamJobKey spec.JobKey, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUpstreamJobDidNotSucceed,
+		Message: fmt.Sprintf("upstream job %s did not succeed (status: %s)", upstreamJobKey.UserString(), jobStatus.Message()),
+	})
+}
+
+func ErrorInvalidCSVDelimiter(delimiter string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCSVDelimiter,
+		Message: fmt.Sprintf("%s is not a valid delimiter; the delimiter must be a single character other than a double quote or a newline", s.UserStr(delimiter)),
+	})
+}
+
+func ErrorDuplicateCSVColumn(column string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDuplicateCSVColumn,
+		Message: fmt.Sprintf("the header row contains the column %s more than once; each column name is used as a key of the json object which is created for each row, so column names must be unique", s.UserStr(column)),
+	})
+}
+
+func ErrorInvalidJobStatusTransition(jobKey spec.JobKey, currentStatus status.JobCode, newStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobStatusTransition,
+		Message: fmt.Sprintf("cannot change the status of batch job %s from %s to %s", jobKey.UserString(), s.UserStr(currentStatus.Message()), s.UserStr(newStatus.Message())),
+	})
+}
+
+func ErrorJobStateConflict(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobStateConflict,
+		Message: fmt.Sprintf("unable to update the state of batch job %s because it is being modified concurrently; please try again", jobKey.UserString()),
+	})
+}
+
+func ErrorInvalidJobStoreBackend(backend string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobStoreBackend,
+		Message: fmt.Sprintf("%s is not a valid job store backend (CORTEX_BATCH_JOB_STORE); valid backends are %s", s.UserStr(backend), s.UserStrsOr(JobStoreBackends)),
+	})
+}
+
+func ErrorJobIsNotRunning(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobIsNotRunning,
+		Message: fmt.Sprintf("batch job %s is not running (status: %s)", jobKey.UserString(), jobStatus.Message()),
+	})
+}
+
+func ErrorJobIsNotPaused(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobIsNotPaused,
+		Message: fmt.Sprintf("cannot resume batch job %s because it is not paused (status: %s)", jobKey.UserString(), jobStatus.Message()),
+	})
+}
+
+func ErrorNotEnoughClusterCapacity(workers int, maxWorkers int64, workersPerInstance int64, maxInstances int64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNotEnoughClusterCapacity,
+		Message: fmt.Sprintf("cannot run %d workers because the cluster can run at most %d workers of this api (%d per instance, and max_instances is %d)", workers, maxWorkers, workersPerInstance, maxInstances),
+	})
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+const (
+	_failedBatchesFile = "failed_batches.json"
+	_retryOfLabelKey   = "retry_of" // set on jobs which retry the failed batches of another job
+	// long enough for all of the messages to be received before any of them become visible again
+	_deadLetterQueueReceiveVisibilityTimeout = 10 * time.Minute
+	_deadLetterQueueReceiveWaitTime          = 1 * time.Second
+)
+
+func failedBatchesKey(jobKey spec.JobKey) string {
+	return path.Join(jobKey.Prefix(), _failedBatchesFile)
+}
+
+// failed batches are saved when the job's runtime resources are deleted, since the dead letter queue is deleted along with the job's queue
+func readFailedBatches(jobKey spec.JobKey) ([]schema.FailedBatch, error) {
+	failedBatches := []schema.FailedBatch{}
+
+	exists, err := config.AWS.IsS3File(config.Cluster.Bucket, failedBatchesKey(jobKey))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return failedBatches, nil
+	}
+
+	err = config.AWS.ReadJSONFromS3(&failedBatches, config.Cluster.Bucket, failedBatchesKey(jobKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read failed batches", jobKey.UserString())
+	}
+
+	return failedBatches, nil
+}
+
+// moves the batches from the job's dead letter queue (if it has one) to the cluster's bucket, and deletes the dead letter queue
+func saveAndDeleteDeadLetterQueue(jobKey spec.JobKey) error {
+	exists, err := doesDeadLetterQueueExist(jobKey)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	deadLetterQueueURL, err := getJobDeadLetterQueueURL(jobKey)
+	if err != nil {
+		return err
+	}
+
+	// merge with the batches from a previous attempt, in case the dead letter queue wasn't deleted
+	failedBatches, err := readFailedBatches(jobKey)
+	if err != nil {
+		return err
+	}
+
+	savedBatchIDs := make(map[string]bool, len(failedBatches))
+	for _, failedBatch := range failedBatches {
+		savedBatchIDs[failedBatch.ID] = true
+	}
+
+	for {
+		output, err := config.AWS.SQS().ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(deadLetterQueueURL),
+			MaxNumberOfMessages:   aws.Int64(10),
+			MessageAttributeNames: aws.StringSlice([]string{"All"}),
+			VisibilityTimeout:     aws.Int64(int64(_deadLetterQueueReceiveVisibilityTimeout.Seconds())),
+			WaitTimeSeconds:       aws.Int64(int64(_deadLetterQueueReceiveWaitTime.Seconds())),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to receive messages from dead letter queue", deadLetterQueueURL)
+		}
+
+		if len(output.Messages) == 0 {
+			break
+		}
+
+		for _, message := range output.Messages {
+			// the job_complete placeholder isn't a batch
+			if _, ok := message.MessageAttributes["job_complete"]; ok {
+				continue
+			}
+			if savedBatchIDs[*message.MessageId] {
+				continue
+			}
+			savedBatchIDs[*message.MessageId] = true
+
+			payload := []byte(*message.Body)
+			if spilledBatch, ok := message.MessageAttributes[_spilledBatchAttribute]; ok {
+				payload, err = config.AWS.ReadBytesFromS3(config.Cluster.Bucket, *spilledBatch.StringValue)
+				if err != nil {
+					return errors.Wrap(err, "failed to read spilled batch", *message.MessageId)
+				}
+			}
+
+			failedBatches = append(failedBatches, schema.FailedBatch{
+				ID:      *message.MessageId,
+				Payload: json.RawMessage(payload),
+			})
+		}
+	}
+
+	if len(failedBatches) > 0 {
+		err = config.AWS.UploadJSONToS3(failedBatches, config.Cluster.Bucket, failedBatchesKey(jobKey))
+		if err != nil {
+			return errors.Wrap(err, "failed to save failed batches", jobKey.UserString())
+		}
+		writeToJobLogStream(jobKey, fmt.Sprintf("saved %d failed batches", len(failedBatches)))
+	}
+
+	return deleteQueueByURL(deadLetterQueueURL)
+}
+
+func GetFailedBatches(jobKey spec.JobKey) (*schema.GetFailedBatchesResponse, error) {
+	jobState, err := getJobState(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobState.Status.IsInProgress() {
+		return nil, ErrorFailedBatchesNotAvailable(jobKey)
+	}
+
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	failedBatches, err := readFailedBatches(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.GetFailedBatchesResponse{
+		JobKey:        jobKey,
+		MaxRetries:    jobSpec.MaxRetries,
+		FailedBatches: failedBatches,
+	}, nil
+}
+
+// RetryFailedBatches submits a new job with the failed batches of a completed job, using the same job configuration
+func RetryFailedBatches(jobKey spec.JobKey) (*spec.Job, error) {
+	failedBatchesResponse, err := GetFailedBatches(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failedBatchesResponse.FailedBatches) == 0 {
+		return nil, ErrorNoFailedBatches(jobKey)
+	}
+
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// every batch is a json list of items, so the items can be re-partitioned into batches of the original size
+	items := []json.RawMessage{}
+	batchSize := 1
+	for _, failedBatch := range failedBatchesResponse.FailedBatches {
+		var batchItems []json.RawMessage
+		if err := json.Unmarshal(failedBatch.Payload, &batchItems); err != nil {
+			batchItems = []json.RawMessage{failedBatch.Payload}
+		}
+		items = append(items, batchItems...)
+		if len(batchItems) > batchSize {
+			batchSize = len(batchItems)
+		}
+	}
+
+	submission := schema.JobSubmission{
+		RuntimeJobConfig: retryJobConfig(jobSpec),
+		ItemList: &schema.ItemList{
+			Items:     items,
+			BatchSize: batchSize,
+		},
+	}
+
+	newJobSpec, err := SubmitJob(jobKey.APIName, &submission)
+	if err != nil {
+		return nil, err
+	}
+
+	writeToJobLogStream(newJobSpec.JobKey, fmt.Sprintf("retrying %d failed batches from job %s", len(failedBatchesResponse.FailedBatches), jobKey.ID))
+
+	return newJobSpec, nil
+}
+
+// the retry doesn't depend on the original job's dependencies (which have already succeeded, and may have since been deleted), and is labeled with the id of the original job so that it can be told apart from it
+func retryJobConfig(jobSpec *spec.Job) spec.RuntimeJobConfig {
+	runtimeJobConfig := jobSpec.RuntimeJobConfig
+	runtimeJobConfig.DependsOn = nil
+
+	runtimeJobConfig.Labels = map[string]string{}
+	for key, value := range jobSpec.Labels {
+		runtimeJobConfig.Labels[key] = value
+	}
+	runtimeJobConfig.Labels[_retryOfLabelKey] = jobSpec.ID
+
+	return runtimeJobConfig
+}
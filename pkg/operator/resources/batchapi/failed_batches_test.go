@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryJobConfig(t *testing.T) {
+	jobSpec := &spec.Job{
+		JobKey: spec.JobKey{APIName: "test", ID: "69b93378fa5c0218"},
+		RuntimeJobConfig: spec.RuntimeJobConfig{
+			Workers:    2,
+			MaxRetries: pointer.Int(3),
+			DependsOn:  []spec.JobKey{{APIName: "upstream", ID: "69b93378fa5c0217"}},
+			Labels:     map[string]string{"team": "ml"},
+		},
+	}
+
+	runtimeJobConfig := retryJobConfig(jobSpec)
+
+	require.Equal(t, 2, runtimeJobConfig.Workers)
+	require.Equal(t, 3, *runtimeJobConfig.MaxRetries)
+	require.Empty(t, runtimeJobConfig.DependsOn)
+	require.Equal(t, map[string]string{"team": "ml", _retryOfLabelKey: "69b93378fa5c0218"}, runtimeJobConfig.Labels)
+
+	// the original job's spec is not modified
+	require.Len(t, jobSpec.DependsOn, 1)
+	require.Equal(t, map[string]string{"team": "ml"}, jobSpec.Labels)
+}
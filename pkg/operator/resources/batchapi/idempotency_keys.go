@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+const (
+	_idempotencyKeyWindow    = 24 * time.Hour
+	_maxIdempotencyKeyLength = 256
+)
+
+// submissions with the same idempotency key are serialized, so that concurrent retries of a submission don't both submit a job
+var (
+	_idempotencyKeyLocks      = map[string]*idempotencyKeyLock{} // record key -> lock
+	_idempotencyKeyLocksMutex = sync.Mutex{}
+)
+
+type idempotencyKeyLock struct {
+	sync.Mutex
+	refCount int // the number of submissions which hold or are waiting for the lock
+}
+
+type idempotencyKeyRecord struct {
+	JobID         string    `json:"job_id"`
+	SubmittedTime time.Time `json:"submitted_time"`
+}
+
+// the keys are hashed since they are user-provided; the records are deleted along with the API's other files in the cluster's bucket
+func idempotencyKeyRecordKey(apiName string, idempotencyKey string) string {
+	return filepath.Join("apis", apiName, "idempotency_keys", hash.String(idempotencyKey)+".json")
+}
+
+// lockIdempotencyKey blocks until no other submission to the API holds the idempotency key, and returns a function which releases it
+func lockIdempotencyKey(apiName string, idempotencyKey string) func() {
+	recordKey := idempotencyKeyRecordKey(apiName, idempotencyKey)
+
+	_idempotencyKeyLocksMutex.Lock()
+	lock, ok := _idempotencyKeyLocks[recordKey]
+	if !ok {
+		lock = &idempotencyKeyLock{}
+		_idempotencyKeyLocks[recordKey] = lock
+	}
+	lock.refCount++
+	_idempotencyKeyLocksMutex.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		_idempotencyKeyLocksMutex.Lock()
+		defer _idempotencyKeyLocksMutex.Unlock()
+		lock.refCount--
+		if lock.refCount == 0 {
+			delete(_idempotencyKeyLocks, recordKey)
+		}
+	}
+}
+
+func validateIdempotencyKey(idempotencyKey string) error {
+	if idempotencyKey == "" {
+		return cr.ErrorCannotBeEmpty()
+	}
+	if len(idempotencyKey) > _maxIdempotencyKeyLength {
+		return cr.ErrorTooLong(idempotencyKey, _maxIdempotencyKeyLength)
+	}
+	return nil
+}
+
+// Returns the job which was submitted with the idempotency key within the idempotency window, or nil if there isn't one; must be called with the idempotency key locked
+func getJobForIdempotencyKey(apiName string, idempotencyKey string) (*spec.Job, error) {
+	key := idempotencyKeyRecordKey(apiName, idempotencyKey)
+
+	exists, err := config.AWS.IsS3File(config.Cluster.Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	record := idempotencyKeyRecord{}
+	if err := config.AWS.ReadJSONFromS3(&record, config.Cluster.Bucket, key); err != nil {
+		return nil, err
+	}
+	if time.Since(record.SubmittedTime) > _idempotencyKeyWindow {
+		return nil, nil
+	}
+
+	// the job's files may have been deleted by the job retention period
+	jobKey := spec.JobKey{APIName: apiName, ID: record.JobID}
+	exists, err = config.AWS.IsS3File(config.Cluster.Bucket, jobKey.SpecFilePath())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	return downloadJobSpec(jobKey)
+}
+
+// must be called with the idempotency key locked
+func saveIdempotencyKeyRecord(idempotencyKey string, jobSpec *spec.Job) error {
+	record := idempotencyKeyRecord{
+		JobID:         jobSpec.ID,
+		SubmittedTime: jobSpec.StartTime,
+	}
+	return config.AWS.UploadJSONToS3(record, config.Cluster.Bucket, idempotencyKeyRecordKey(jobSpec.APIName, idempotencyKey))
+}
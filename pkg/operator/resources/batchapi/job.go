@@ -21,11 +21,13 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	kbatch "k8s.io/api/batch/v1"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
 )
@@ -54,10 +56,39 @@ func DryRun(submission *schema.JobSubmission) ([]string, error) {
 		return s3Files, nil
 	}
 
+	if submission.CSVFiles != nil {
+		s3Files, err := listFilesDryRun(&submission.CSVFiles.S3Lister)
+		if err != nil {
+			return nil, errors.Wrap(err, schema.CSVFilesKey)
+		}
+
+		return s3Files, nil
+	}
+
 	return nil, nil
 }
 
 func SubmitJob(apiName string, submission *schema.JobSubmission) (*spec.Job, error) {
+	// the existing job is returned before the submission is validated, since it may no longer be valid (e.g. if an upstream job has since failed)
+	if submission.IdempotencyKey != nil {
+		if err := validateIdempotencyKey(*submission.IdempotencyKey); err != nil {
+			return nil, errors.Wrap(err, schema.IdempotencyKeyKey)
+		}
+
+		// the key is released once the submission has returned, by which time its record has been written
+		unlockIdempotencyKey := lockIdempotencyKey(apiName, *submission.IdempotencyKey)
+		defer unlockIdempotencyKey()
+
+		jobSpec, err := getJobForIdempotencyKey(apiName, *submission.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if jobSpec != nil {
+			writeToJobLogStream(jobSpec.JobKey, "returned this job for a submission with the same idempotency key")
+			return jobSpec, nil
+		}
+	}
+
 	err := validateJobSubmission(submission)
 	if err != nil {
 		return nil, err
@@ -88,7 +119,7 @@ func SubmitJob(apiName string, submission *schema.JobSubmission) (*spec.Job, err
 		"jobID":   jobID,
 	}
 
-	queueURL, err := createFIFOQueue(jobKey, tags)
+	queueURL, err := createFIFOQueue(jobKey, submission.RuntimeJobConfig, tags)
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +146,16 @@ func SubmitJob(apiName string, submission *schema.JobSubmission) (*spec.Job, err
 		return nil, err
 	}
 
+	if len(submission.DependsOn) > 0 {
+		err = waitForJobDependencies(&jobSpec, submission)
+		if err != nil {
+			deleteQueueByURL(queueURL)
+			return nil, err
+		}
+		recordIdempotencyKey(submission, &jobSpec)
+		return &jobSpec, nil
+	}
+
 	err = setEnqueuingStatus(jobKey)
 	if err != nil {
 		deleteQueueByURL(queueURL)
@@ -127,11 +168,24 @@ func SubmitJob(apiName string, submission *schema.JobSubmission) (*spec.Job, err
 		return nil, err
 	}
 
+	recordIdempotencyKey(submission, &jobSpec)
+
 	go deployJob(apiSpec, &jobSpec, submission)
 
 	return &jobSpec, nil
 }
 
+// the job has already been submitted, so it is returned even if its idempotency key couldn't be recorded
+func recordIdempotencyKey(submission *schema.JobSubmission, jobSpec *spec.Job) {
+	if submission.IdempotencyKey == nil {
+		return
+	}
+	if err := saveIdempotencyKeyRecord(*submission.IdempotencyKey, jobSpec); err != nil {
+		telemetry.Error(err)
+		errors.PrintError(err)
+	}
+}
+
 func downloadJobSpec(jobKey spec.JobKey) (*spec.Job, error) {
 	jobSpec := spec.Job{}
 	err := config.AWS.ReadJSONFromS3(&jobSpec, config.Cluster.Bucket, jobKey.SpecFilePath())
@@ -167,7 +221,7 @@ func deployJob(apiSpec *spec.API, jobSpec *spec.Job, submission *schema.JobSubmi
 	if totalBatches == 0 {
 		var errs []error
 		writeToJobLogStream(jobSpec.JobKey, ErrorNoDataFoundInJobSubmission().Error())
-		if submission.DelimitedFiles != nil {
+		if submission.DelimitedFiles != nil || submission.CSVFiles != nil {
 			errs = append(errs, writeToJobLogStream(jobSpec.JobKey, "please verify that the files are not empty (the files being read can be retrieved by providing `dryRun=true` query param with your job submission"))
 		}
 		errs = append(errs, setEnqueueFailedStatus(jobSpec.JobKey))
@@ -229,6 +283,42 @@ func createK8sJob(apiSpec *spec.API, jobSpec *spec.Job) error {
 	return nil
 }
 
+// the number of workers which have been terminated by pausing or resizing the job
+const _terminatedWorkersAnnotationKey = "batch.cortex.dev/terminated-workers"
+
+// the number of workers of a job is the parallelism of its kubernetes job; when it is decreased, the job controller terminates the excess workers, which finish their current batch and then exit with an error (so that the job isn't considered complete), and are therefore counted as failed by kubernetes; they are recorded on the kubernetes job to be excluded from its failures (see hasWorkerFailures)
+func setK8sJobParallelism(jobKey spec.JobKey, workers int) error {
+	k8sJob, err := config.K8s.GetJob(jobKey.K8sName())
+	if err != nil {
+		return err
+	}
+	if k8sJob == nil {
+		return errors.ErrorUnexpected("unable to find kubernetes job", jobKey.UserString())
+	}
+
+	parallelism := int32(workers)
+	if terminatedWorkers := k8sJob.Status.Active - parallelism; terminatedWorkers > 0 {
+		if k8sJob.Annotations == nil {
+			k8sJob.Annotations = map[string]string{}
+		}
+		k8sJob.Annotations[_terminatedWorkersAnnotationKey] = s.Int32(getTerminatedWorkers(k8sJob) + terminatedWorkers)
+	}
+	k8sJob.Spec.Parallelism = &parallelism
+
+	_, err = config.K8s.UpdateJob(k8sJob)
+	return err
+}
+
+func getTerminatedWorkers(k8sJob *kbatch.Job) int32 {
+	terminatedWorkers, _ := s.ParseInt32(k8sJob.Annotations[_terminatedWorkersAnnotationKey])
+	return terminatedWorkers
+}
+
+// returns whether any of the job's workers have failed, excluding those which were terminated by pausing or resizing the job
+func hasWorkerFailures(k8sJob *kbatch.Job) bool {
+	return k8sJob.Status.Failed > getTerminatedWorkers(k8sJob)
+}
+
 func deleteK8sJob(jobKey spec.JobKey) error {
 	_, err := config.K8s.DeleteJobs(&kmeta.ListOptions{
 		LabelSelector: klabels.SelectorFromSet(map[string]string{"apiName": jobKey.APIName, "jobID": jobKey.ID}).String(),
@@ -244,13 +334,15 @@ func deleteJobRuntimeResources(jobKey spec.JobKey) error {
 	err := errors.FirstError(
 		deleteK8sJob(jobKey),
 		deleteQueueByJobKey(jobKey),
+		saveAndDeleteDeadLetterQueue(jobKey),
 	)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// the dead letter queue may contain spilled batches, so they are only deleted once the failed batches have been saved
+	return config.AWS.DeleteS3Prefix(config.Cluster.Bucket, spilledBatchesPrefix(jobKey), true)
 }
 
 func StopJob(jobKey spec.JobKey) error {
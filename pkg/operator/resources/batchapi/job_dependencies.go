@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"path"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	_maxJobDependencies = 20
+	_jobSubmissionFile  = "submission.json"
+)
+
+// the submission of a job which is waiting for its upstream jobs is stored until the job starts, since its batches are enqueued then
+func jobSubmissionKey(jobKey spec.JobKey) string {
+	return path.Join(jobKey.Prefix(), _jobSubmissionFile)
+}
+
+func validateJobDependencies(dependsOn []spec.JobKey) error {
+	if len(dependsOn) > _maxJobDependencies {
+		return errors.Wrap(cr.ErrorTooManyElements(_maxJobDependencies), schema.DependsOnKey)
+	}
+
+	for i, upstreamJobKey := range dependsOn {
+		if upstreamJobKey.APIName == "" {
+			return errors.Wrap(cr.ErrorMustBeDefined(), schema.DependsOnKey, s.Int(i), schema.APINameKey)
+		}
+		if upstreamJobKey.ID == "" {
+			return errors.Wrap(cr.ErrorMustBeDefined(), schema.DependsOnKey, s.Int(i), schema.JobIDKey)
+		}
+
+		jobState, err := getJobState(upstreamJobKey)
+		if err != nil {
+			return errors.Wrap(err, schema.DependsOnKey, s.Int(i))
+		}
+
+		if jobState.Status.IsCompleted() && jobState.Status != status.JobSucceeded {
+			return errors.Wrap(ErrorUpstreamJobDidNotSucceed(upstreamJobKey, jobState.Status), schema.DependsOnKey, s.Int(i))
+		}
+	}
+
+	return nil
+}
+
+// waitForJobDependencies is called in place of deploying the job if it has upstream jobs; the job is started by ManageJobResources
+func waitForJobDependencies(jobSpec *spec.Job, submission *schema.JobSubmission) error {
+	if err := config.AWS.UploadJSONToS3(submission, config.Cluster.Bucket, jobSubmissionKey(jobSpec.JobKey)); err != nil {
+		return err
+	}
+
+	if err := setWaitingStatus(jobSpec.JobKey); err != nil {
+		return err
+	}
+
+	upstreamJobs := make([]string, len(jobSpec.DependsOn))
+	for i, upstreamJobKey := range jobSpec.DependsOn {
+		upstreamJobs[i] = upstreamJobKey.UserString()
+	}
+
+	return writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("waiting for %s to succeed", s.StrsAnd(upstreamJobs)))
+}
+
+// checkJobDependencies starts the waiting job if all of its upstream jobs have succeeded, or fails it if any of them did not succeed
+func checkJobDependencies(jobKey spec.JobKey) error {
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return err
+	}
+
+	allSucceeded := true
+	for _, upstreamJobKey := range jobSpec.DependsOn {
+		jobState, err := getJobState(upstreamJobKey)
+		if err != nil {
+			// the upstream job's files are deleted along with its api
+			if errors.GetKind(err) == ErrJobNotFound {
+				return failWaitingJob(jobKey, fmt.Sprintf("terminating job %s; upstream job %s was not found", jobKey.UserString(), upstreamJobKey.UserString()))
+			}
+			return err
+		}
+
+		if jobState.Status.IsCompleted() && jobState.Status != status.JobSucceeded {
+			return failWaitingJob(jobKey, fmt.Sprintf("terminating job %s; upstream job %s did not succeed (status: %s)", jobKey.UserString(), upstreamJobKey.UserString(), jobState.Status.Message()))
+		}
+
+		if jobState.Status != status.JobSucceeded {
+			allSucceeded = false
+		}
+	}
+
+	if !allSucceeded {
+		return nil
+	}
+
+	return startWaitingJob(jobSpec)
+}
+
+func failWaitingJob(jobKey spec.JobKey, reason string) error {
+	return errors.FirstError(
+		writeToJobLogStream(jobKey, reason),
+		setDependencyFailedStatus(jobKey),
+		deleteJobRuntimeResources(jobKey),
+		config.AWS.DeleteS3File(config.Cluster.Bucket, jobSubmissionKey(jobKey)),
+	)
+}
+
+func startWaitingJob(jobSpec *spec.Job) error {
+	jobKey := jobSpec.JobKey
+
+	submission := schema.JobSubmission{}
+	if err := config.AWS.ReadJSONFromS3(&submission, config.Cluster.Bucket, jobSubmissionKey(jobKey)); err != nil {
+		return err
+	}
+
+	apiSpec, err := operator.DownloadAPISpec(jobKey.APIName, jobSpec.APIID)
+	if err != nil {
+		return err
+	}
+
+	if err := setEnqueuingStatus(jobKey); err != nil {
+		return err
+	}
+
+	writeToJobLogStream(jobKey, "upstream jobs succeeded; started enqueuing batches")
+
+	go deployJob(apiSpec, jobSpec, &submission)
+
+	// the submission is no longer needed once it has been read
+	return config.AWS.DeleteS3File(config.Cluster.Bucket, jobSubmissionKey(jobKey))
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const (
+	DefaultJobHistoryLimit      = 20
+	MaxJobHistoryLimit          = 100
+	DeleteExpiredJobsCronPeriod = 1 * time.Hour
+	_jobHistoryListingPageSize  = 50   // the number of job states which are listed from the job store at a time while searching for matching jobs
+	_jobHistoryMaxScannedJobs   = 1000 // the number of job states which are searched per request; if no more matching jobs are found, the client can continue from the next page
+)
+
+// GetJobHistory returns up to query.Limit of the API's jobs which match the query, most recently submitted first; fewer jobs may be returned along with the next page if the search stops at _jobHistoryMaxScannedJobs
+func GetJobHistory(apiName string, query schema.JobHistoryQuery) (*schema.JobHistoryResponse, error) {
+	startAfterJobID := query.After
+	if query.SubmittedBefore != nil {
+		// jobs which were submitted before the time have greater ids
+		if beforeID := spec.MonotonicallyDecreasingIDForTime(*query.SubmittedBefore); beforeID > startAfterJobID {
+			startAfterJobID = beforeID
+		}
+	}
+
+	// jobs which were submitted after the time have smaller ids
+	submittedAfterID := ""
+	if query.SubmittedAfter != nil {
+		submittedAfterID = spec.MonotonicallyDecreasingIDForTime(*query.SubmittedAfter)
+	}
+
+	response := schema.JobHistoryResponse{
+		APIName:     apiName,
+		JobStatuses: []status.JobStatus{},
+	}
+
+	scannedJobs := 0
+	for {
+		jobStates, err := _jobStore.ListJobStates(apiName, startAfterJobID, _jobHistoryListingPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, jobState := range jobStates {
+			if submittedAfterID != "" && jobState.ID > submittedAfterID {
+				return &response, nil
+			}
+
+			if scannedJobs == _jobHistoryMaxScannedJobs {
+				response.NextPage = startAfterJobID
+				return &response, nil
+			}
+			scannedJobs++
+			startAfterJobID = jobState.ID
+
+			if query.Status != nil && jobState.Status != *query.Status {
+				continue
+			}
+
+			if query.LabelKey != "" {
+				jobSpec, err := downloadJobSpec(jobState.JobKey)
+				if err != nil {
+					return nil, err
+				}
+				if !jobHasLabel(jobSpec, query.LabelKey, query.LabelValue) {
+					continue
+				}
+			}
+
+			var jobStatus *status.JobStatus
+			if jobState.Status.IsInProgress() {
+				jobStatus, err = getJobStatusWithK8sResources(jobState)
+			} else {
+				jobStatus, err = getJobStatusFromJobState(jobState, nil, nil)
+			}
+			if err != nil {
+				return nil, err
+			}
+			response.JobStatuses = append(response.JobStatuses, *jobStatus)
+
+			if len(response.JobStatuses) == query.Limit {
+				response.NextPage = jobState.ID
+				return &response, nil
+			}
+		}
+
+		if len(jobStates) == 0 {
+			return &response, nil
+		}
+	}
+}
+
+func jobHasLabel(jobSpec *spec.Job, key string, value string) bool {
+	labelValue, ok := jobSpec.Labels[key]
+	if !ok {
+		return false
+	}
+	return value == "" || labelValue == value
+}
+
+// DeleteExpiredJobs deletes the files and states of the jobs which were submitted more than batch_job_retention_days ago (if it is set in the cluster configuration); jobs which are still in progress are kept
+func DeleteExpiredJobs() error {
+	if config.Cluster.BatchJobRetentionDays == nil {
+		return nil
+	}
+	cutoff := time.Now().Add(-time.Duration(*config.Cluster.BatchJobRetentionDays) * 24 * time.Hour)
+
+	virtualServices, err := config.K8s.ListVirtualServicesByLabel("apiKind", userconfig.BatchAPIKind.String())
+	if err != nil {
+		return err
+	}
+
+	inProgressJobKeys, err := listAllInProgressJobKeys()
+	if err != nil {
+		return err
+	}
+	inProgressJobIDs := strset.New()
+	for _, jobKey := range inProgressJobKeys {
+		inProgressJobIDs.Add(jobKey.ID)
+	}
+
+	var errs []error
+	for _, virtualService := range virtualServices {
+		apiName := virtualService.Labels["apiName"]
+		if err := deleteExpiredJobsForAPI(apiName, cutoff, inProgressJobIDs); err != nil {
+			errs = append(errs, errors.Wrap(err, "job retention", apiName))
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func deleteExpiredJobsForAPI(apiName string, cutoff time.Time, inProgressJobIDs strset.Set) error {
+	prefix := s.EnsureSuffix(spec.BatchAPIJobPrefix(apiName), "/")
+
+	// jobs which were submitted before the cutoff have greater ids, so their files are listed after it
+	startAfter := prefix + spec.MonotonicallyDecreasingIDForTime(cutoff)
+
+	expiredJobIDs := strset.New()
+	err := config.AWS.S3BatchIteratorStartAfter(config.Cluster.Bucket, prefix, startAfter, false, nil, func(objects []*s3.Object) (bool, error) {
+		for _, object := range objects {
+			jobID := strings.Split(strings.TrimPrefix(*object.Key, prefix), "/")[0]
+			if !inProgressJobIDs.Has(jobID) {
+				expiredJobIDs.Add(jobID)
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for jobID := range expiredJobIDs {
+		jobKey := spec.JobKey{APIName: apiName, ID: jobID}
+
+		// the state is deleted first, since the job's files are used to find the expired jobs
+		err := errors.FirstError(
+			_jobStore.DeleteJobState(jobKey),
+			config.AWS.DeleteS3Dir(config.Cluster.Bucket, jobKey.Prefix(), true),
+		)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, jobKey.UserString()))
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
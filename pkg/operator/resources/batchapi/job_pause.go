@@ -0,0 +1,117 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"sync"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// changes to the workers of a job are serialized, so that the job's status, spec and kubernetes job stay consistent
+var _jobWorkersMutex = sync.Mutex{}
+
+// PauseJob terminates the workers of a running job once they have finished their current batches; its queue and spec are kept, so that the job can be resumed where it left off
+func PauseJob(jobKey spec.JobKey) error {
+	_jobWorkersMutex.Lock()
+	defer _jobWorkersMutex.Unlock()
+
+	jobState, err := getJobState(jobKey)
+	if err != nil {
+		return err
+	}
+
+	if jobState.Status != status.JobRunning {
+		return ErrorJobIsNotRunning(jobKey, jobState.Status)
+	}
+
+	// the status is set first so that the cron doesn't consider the job to have failed while its workers are terminating
+	if err := setPausedStatus(jobKey); err != nil {
+		return err
+	}
+
+	if err := setK8sJobParallelism(jobKey, 0); err != nil {
+		return errors.FirstError(err, setRunningStatus(jobKey))
+	}
+
+	writeToJobLogStream(jobKey, "job paused; workers have been terminated and the remaining batches will be kept in the queue until the job is resumed")
+
+	return nil
+}
+
+// ResumeJob restores the workers of a paused job; if workers is nil, the job's previous number of workers is used (caller identifies who requested a change to the number of workers in the job's logs)
+func ResumeJob(jobKey spec.JobKey, workers *int, caller string) (*spec.Job, error) {
+	_jobWorkersMutex.Lock()
+	defer _jobWorkersMutex.Unlock()
+
+	if workers != nil && *workers <= 0 {
+		return nil, errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*workers, 1), schema.WorkersKey)
+	}
+
+	jobState, err := getJobState(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobState.Status != status.JobPaused {
+		return nil, ErrorJobIsNotPaused(jobKey, jobState.Status)
+	}
+
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	prevWorkers := jobSpec.Workers
+	if workers != nil && *workers != jobSpec.Workers {
+		if *workers > jobSpec.Workers {
+			if err := validateWorkersCapacity(jobSpec, *workers); err != nil {
+				return nil, errors.Wrap(err, schema.WorkersKey)
+			}
+		}
+
+		jobSpec.Workers = *workers
+		if err := uploadJobSpec(jobSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := setK8sJobParallelism(jobKey, jobSpec.Workers); err != nil {
+		if jobSpec.Workers != prevWorkers {
+			jobSpec.Workers = prevWorkers
+			return nil, errors.FirstError(err, uploadJobSpec(jobSpec))
+		}
+		return nil, err
+	}
+
+	if err := setRunningStatus(jobKey); err != nil {
+		return nil, err
+	}
+
+	if jobSpec.Workers != prevWorkers {
+		writeToJobLogStream(jobKey, fmt.Sprintf("resize requested by %s: number of workers changed from %d to %d", caller, prevWorkers, jobSpec.Workers))
+	}
+	writeToJobLogStream(jobKey, fmt.Sprintf("job resumed with %d %s", jobSpec.Workers, s.PluralS("worker", jobSpec.Workers)))
+
+	return jobSpec, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/stretchr/testify/require"
+	kbatch "k8s.io/api/batch/v1"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func setFakeK8sJob(t *testing.T, jobKey spec.JobKey, parallelism int32, active int32, failed int32) {
+	t.Helper()
+
+	k8sJob := &kbatch.Job{
+		ObjectMeta: kmeta.ObjectMeta{
+			Name:      jobKey.K8sName(),
+			Namespace: "default",
+		},
+		Spec: kbatch.JobSpec{
+			Parallelism: &parallelism,
+		},
+		Status: kbatch.JobStatus{
+			Active: active,
+			Failed: failed,
+		},
+	}
+
+	config.K8s = k8s.NewForClientset("default", kfake.NewSimpleClientset(k8sJob))
+}
+
+// simulates the job controller updating the status of the kubernetes job once its excess workers have been deleted
+func setFakeK8sJobStatus(t *testing.T, jobKey spec.JobKey, active int32, failed int32) *kbatch.Job {
+	t.Helper()
+
+	k8sJob, err := config.K8s.GetJob(jobKey.K8sName())
+	require.NoError(t, err)
+	k8sJob.Status.Active = active
+	k8sJob.Status.Failed = failed
+
+	k8sJob, err = config.K8s.UpdateJob(k8sJob)
+	require.NoError(t, err)
+	return k8sJob
+}
+
+func TestPausedJobWorkerFailures(t *testing.T) {
+	prevK8s := config.K8s
+	defer func() { config.K8s = prevK8s }()
+
+	jobKey := spec.JobKey{APIName: "test", ID: "69b93378fa5c0218"}
+	setFakeK8sJob(t, jobKey, 3, 3, 0)
+
+	// pause
+	require.NoError(t, setK8sJobParallelism(jobKey, 0))
+	k8sJob := setFakeK8sJobStatus(t, jobKey, 0, 3)
+	require.Equal(t, int32(0), *k8sJob.Spec.Parallelism)
+	require.False(t, hasWorkerFailures(k8sJob))
+
+	// resume
+	require.NoError(t, setK8sJobParallelism(jobKey, 3))
+	k8sJob = setFakeK8sJobStatus(t, jobKey, 3, 3)
+	require.Equal(t, int32(3), *k8sJob.Spec.Parallelism)
+	require.False(t, hasWorkerFailures(k8sJob))
+
+	// a worker fails after the job was resumed
+	k8sJob = setFakeK8sJobStatus(t, jobKey, 2, 4)
+	require.True(t, hasWorkerFailures(k8sJob))
+}
+
+func TestWorkerFailuresWithoutPause(t *testing.T) {
+	prevK8s := config.K8s
+	defer func() { config.K8s = prevK8s }()
+
+	jobKey := spec.JobKey{APIName: "test", ID: "69b93378fa5c0218"}
+	setFakeK8sJob(t, jobKey, 2, 1, 1)
+
+	k8sJob, err := config.K8s.GetJob(jobKey.K8sName())
+	require.NoError(t, err)
+	require.True(t, hasWorkerFailures(k8sJob))
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/metrics"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	_jobThroughputWindow    = 5 * time.Minute
+	_minJobThroughputWindow = 30 * time.Second // the throughput of a job which just started running would be skewed by its workers initializing
+)
+
+func getJobProgress(jobState *JobState, totalBatchCount int, batchMetrics metrics.BatchMetrics, workerCounts status.WorkerCounts) (*status.JobProgress, error) {
+	jobProgress := status.JobProgress{}
+
+	completedBatches := batchMetrics.TotalCompleted()
+	if totalBatchCount > 0 {
+		jobProgress.PercentComplete = 100 * float64(completedBatches) / float64(totalBatchCount)
+		if jobProgress.PercentComplete > 100 {
+			jobProgress.PercentComplete = 100
+		}
+	}
+
+	windowEnd := time.Now().Truncate(time.Second)
+	windowStart := windowEnd.Add(-_jobThroughputWindow)
+	if runningTime, ok := jobState.LastUpdatedMap[status.JobRunning.String()]; ok && runningTime.After(windowStart) {
+		windowStart = runningTime.Truncate(time.Second)
+	}
+
+	window := windowEnd.Sub(windowStart)
+	if window < _minJobThroughputWindow {
+		return &jobProgress, nil
+	}
+
+	windowMetrics := metrics.BatchMetrics{}
+	err := getMetricsFunc(&jobState.JobKey, 1, &windowStart, &windowEnd, &windowMetrics)()
+	if err != nil {
+		return nil, err
+	}
+
+	batchesPerSecond := float64(windowMetrics.TotalCompleted()) / window.Seconds()
+	jobProgress.BatchesPerSecond = &batchesPerSecond
+
+	if workerCounts.Running > 0 {
+		batchesPerSecondPerWorker := batchesPerSecond / float64(workerCounts.Running)
+		jobProgress.BatchesPerSecondPerWorker = &batchesPerSecondPerWorker
+	}
+
+	remainingBatches := totalBatchCount - completedBatches
+	if batchesPerSecond > 0 && remainingBatches >= 0 {
+		estimatedTimeRemaining := float64(remainingBatches) / batchesPerSecond
+		jobProgress.EstimatedTimeRemaining = &estimatedTimeRemaining
+	}
+
+	return &jobProgress, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// ResizeJob changes the number of workers of a running job; workers which are removed finish the batches that they are processing before they exit (caller identifies who requested the change in the job's logs)
+func ResizeJob(jobKey spec.JobKey, workers int, caller string) (*spec.Job, error) {
+	_jobWorkersMutex.Lock()
+	defer _jobWorkersMutex.Unlock()
+
+	if workers <= 0 {
+		return nil, errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(workers, 1), schema.WorkersKey)
+	}
+
+	jobState, err := getJobState(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobState.Status != status.JobRunning {
+		return nil, ErrorJobIsNotRunning(jobKey, jobState.Status)
+	}
+
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if workers == jobSpec.Workers {
+		return jobSpec, nil
+	}
+
+	// the job's workers may have been submitted before the cluster's capacity was reduced, so decreasing them is always allowed
+	if workers > jobSpec.Workers {
+		if err := validateWorkersCapacity(jobSpec, workers); err != nil {
+			return nil, errors.Wrap(err, schema.WorkersKey)
+		}
+	}
+
+	// the spec is uploaded first so that workers which are started by the new parallelism read the new number of workers
+	prevWorkers := jobSpec.Workers
+	jobSpec.Workers = workers
+	if err := uploadJobSpec(jobSpec); err != nil {
+		return nil, err
+	}
+
+	if err := setK8sJobParallelism(jobKey, workers); err != nil {
+		jobSpec.Workers = prevWorkers
+		return nil, errors.FirstError(err, uploadJobSpec(jobSpec))
+	}
+
+	writeToJobLogStream(jobKey, fmt.Sprintf("resize requested by %s: number of workers changed from %d to %d", caller, prevWorkers, workers))
+
+	return jobSpec, nil
+}
+
+// validateWorkersCapacity checks that the cluster could run the number of workers if it was scaled up to max_instances (the capacity may also be used by other apis, so the workers are not guaranteed to be scheduled)
+func validateWorkersCapacity(jobSpec *spec.Job, workers int) error {
+	apiSpec, err := operator.DownloadAPISpec(jobSpec.APIName, jobSpec.APIID)
+	if err != nil {
+		return err
+	}
+
+	workersPerInstance, err := maxWorkersPerInstance(apiSpec.Compute)
+	if err != nil {
+		return err
+	}
+	if workersPerInstance == nil {
+		return nil
+	}
+
+	maxInstances := *config.Cluster.MaxInstances
+	maxWorkers := *workersPerInstance * maxInstances
+	if int64(workers) > maxWorkers {
+		return ErrorNotEnoughClusterCapacity(workers, maxWorkers, *workersPerInstance, maxInstances)
+	}
+
+	return nil
+}
+
+// returns nil if the api doesn't request any compute
+func maxWorkersPerInstance(compute *userconfig.Compute) (*int64, error) {
+	if compute == nil {
+		return nil, nil
+	}
+
+	maxMem, err := operator.UpdateMemoryCapacityConfigMap()
+	if err != nil {
+		return nil, err
+	}
+
+	return workersFittingInstance(compute, operator.InstanceUserCapacity(maxMem)), nil
+}
+
+// returns the number of workers which fit on an instance with the given capacity, or nil if compute doesn't request any resources
+func workersFittingInstance(compute *userconfig.Compute, capacity userconfig.Compute) *int64 {
+	if compute == nil {
+		return nil
+	}
+
+	var workersPerResource []int64
+	if compute.CPU != nil && compute.CPU.MilliValue() > 0 {
+		workersPerResource = append(workersPerResource, capacity.CPU.MilliValue()/compute.CPU.MilliValue())
+	}
+	if compute.Mem != nil && compute.Mem.Value() > 0 {
+		workersPerResource = append(workersPerResource, capacity.Mem.Value()/compute.Mem.Value())
+	}
+	if compute.GPU > 0 {
+		workersPerResource = append(workersPerResource, capacity.GPU/compute.GPU)
+	}
+	if compute.Inf > 0 {
+		workersPerResource = append(workersPerResource, capacity.Inf/compute.Inf)
+	}
+
+	if len(workersPerResource) == 0 {
+		return nil
+	}
+
+	minWorkers := libmath.MinInt64(workersPerResource[0], workersPerResource[1:]...)
+	return &minWorkers
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestWorkersFittingInstance(t *testing.T) {
+	capacity := userconfig.Compute{
+		CPU: k8s.WrapQuantity(kresource.MustParse("3500m")),
+		Mem: k8s.WrapQuantity(kresource.MustParse("14Gi")),
+		GPU: 4,
+		Inf: 2,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		compute  *userconfig.Compute
+		expected *int64
+	}{
+		{
+			name:     "nil compute",
+			compute:  nil,
+			expected: nil,
+		},
+		{
+			name:     "no resources requested",
+			compute:  &userconfig.Compute{},
+			expected: nil,
+		},
+		{
+			name:     "cpu",
+			compute:  &userconfig.Compute{CPU: k8s.WrapQuantity(kresource.MustParse("1"))},
+			expected: pointer.Int64(3),
+		},
+		{
+			name:     "mem",
+			compute:  &userconfig.Compute{Mem: k8s.WrapQuantity(kresource.MustParse("4Gi"))},
+			expected: pointer.Int64(3),
+		},
+		{
+			name:     "gpu",
+			compute:  &userconfig.Compute{GPU: 3},
+			expected: pointer.Int64(1),
+		},
+		{
+			name:     "inf",
+			compute:  &userconfig.Compute{Inf: 1},
+			expected: pointer.Int64(2),
+		},
+		{
+			name: "most constrained resource",
+			compute: &userconfig.Compute{
+				CPU: k8s.WrapQuantity(kresource.MustParse("500m")),
+				Mem: k8s.WrapQuantity(kresource.MustParse("1Gi")),
+				GPU: 2,
+			},
+			expected: pointer.Int64(2),
+		},
+		{
+			name:     "doesn't fit",
+			compute:  &userconfig.Compute{CPU: k8s.WrapQuantity(kresource.MustParse("4"))},
+			expected: pointer.Int64(0),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, workersFittingInstance(tc.compute, capacity))
+		})
+	}
+}
+
+func TestDecreasedWorkersFailures(t *testing.T) {
+	prevK8s := config.K8s
+	defer func() { config.K8s = prevK8s }()
+
+	jobKey := spec.JobKey{APIName: "test", ID: "69b93378fa5c0218"}
+	setFakeK8sJob(t, jobKey, 4, 4, 0)
+
+	require.NoError(t, setK8sJobParallelism(jobKey, 2))
+	k8sJob := setFakeK8sJobStatus(t, jobKey, 2, 2)
+	require.Equal(t, int32(2), *k8sJob.Spec.Parallelism)
+	require.False(t, hasWorkerFailures(k8sJob))
+
+	// the terminated workers accumulate across resizes
+	require.NoError(t, setK8sJobParallelism(jobKey, 1))
+	k8sJob = setFakeK8sJobStatus(t, jobKey, 1, 3)
+	require.False(t, hasWorkerFailures(k8sJob))
+
+	// increasing the number of workers doesn't terminate any
+	require.NoError(t, setK8sJobParallelism(jobKey, 3))
+	k8sJob = setFakeK8sJobStatus(t, jobKey, 3, 3)
+	require.Equal(t, int32(3), *k8sJob.Spec.Parallelism)
+	require.False(t, hasWorkerFailures(k8sJob))
+
+	k8sJob = setFakeK8sJobStatus(t, jobKey, 2, 4)
+	require.True(t, hasWorkerFailures(k8sJob))
+}
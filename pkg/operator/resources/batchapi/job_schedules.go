@@ -0,0 +1,332 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const (
+	RunJobSchedulesCronPeriod        = 20 * time.Second
+	_defaultJobScheduleHistoryLimit  = 10
+	_maxJobScheduleHistoryLimit      = 100
+	_jobScheduleMissedRunGracePeriod = 10 * time.Minute // runs which were missed (e.g. while the operator was restarting) are only submitted within this period
+)
+
+// the job schedules are modified by both the operator's endpoints and the schedules cron
+var _jobSchedulesMutex = sync.Mutex{}
+
+// the schedules are kept when the API is updated, and deleted along with the API's other files in the cluster's bucket
+func jobSchedulesKey(apiName string) string {
+	return filepath.Join("apis", apiName, "job_schedules.json")
+}
+
+// must be called with _jobSchedulesMutex held
+func readJobSchedules(apiName string) ([]schema.JobScheduleStatus, error) {
+	key := jobSchedulesKey(apiName)
+
+	jobSchedules := []schema.JobScheduleStatus{}
+	exists, err := config.AWS.IsS3File(config.Cluster.Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if err := config.AWS.ReadJSONFromS3(&jobSchedules, config.Cluster.Bucket, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobSchedules, nil
+}
+
+// must be called with _jobSchedulesMutex held
+func saveJobSchedules(apiName string, jobSchedules []schema.JobScheduleStatus) error {
+	return config.AWS.UploadJSONToS3(jobSchedules, config.Cluster.Bucket, jobSchedulesKey(apiName))
+}
+
+func validateJobSchedule(jobSchedule *schema.JobSchedule) error {
+	if err := urls.CheckDNS1123(jobSchedule.Name); err != nil {
+		return errors.Wrap(err, schema.NameKey)
+	}
+
+	if _, err := cron.ParseSchedule(jobSchedule.Schedule); err != nil {
+		return errors.Wrap(err, schema.ScheduleKey)
+	}
+
+	if jobSchedule.ConcurrencyPolicy == "" {
+		jobSchedule.ConcurrencyPolicy = schema.AllowConcurrencyPolicy
+	}
+	if !slices.HasString(schema.ConcurrencyPolicies, jobSchedule.ConcurrencyPolicy) {
+		return errors.Wrap(cr.ErrorInvalidStr(jobSchedule.ConcurrencyPolicy, schema.ConcurrencyPolicies[0], schema.ConcurrencyPolicies[1:]...), schema.ConcurrencyPolicyKey)
+	}
+
+	if jobSchedule.HistoryLimit == 0 {
+		jobSchedule.HistoryLimit = _defaultJobScheduleHistoryLimit
+	}
+	if jobSchedule.HistoryLimit < 1 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(jobSchedule.HistoryLimit, 1), schema.HistoryLimitKey)
+	}
+	if jobSchedule.HistoryLimit > _maxJobScheduleHistoryLimit {
+		return errors.Wrap(cr.ErrorMustBeLessThanOrEqualTo(jobSchedule.HistoryLimit, _maxJobScheduleHistoryLimit), schema.HistoryLimitKey)
+	}
+
+	if jobSchedule.Submission.IdempotencyKey != nil {
+		return errors.Wrap(ErrorIdempotencyKeyInSchedule(), schema.SubmissionKey, schema.IdempotencyKeyKey)
+	}
+
+	if err := validateJobSubmission(&jobSchedule.Submission); err != nil {
+		return errors.Wrap(err, schema.SubmissionKey)
+	}
+
+	return nil
+}
+
+// CreateJobSchedule creates the schedule, or updates it if the API already has a schedule with the same name (in which case its runs are kept)
+func CreateJobSchedule(apiName string, jobSchedule schema.JobSchedule) (*schema.CreateJobScheduleResponse, error) {
+	if err := validateJobSchedule(&jobSchedule); err != nil {
+		return nil, err
+	}
+
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	jobSchedules, err := readJobSchedules(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobSchedules {
+		if jobSchedules[i].Name == jobSchedule.Name {
+			jobSchedule.CreatedTime = jobSchedules[i].CreatedTime
+			runs := jobSchedules[i].Runs
+			if len(runs) > jobSchedule.HistoryLimit {
+				runs = runs[:jobSchedule.HistoryLimit]
+			}
+			jobSchedules[i] = schema.JobScheduleStatus{JobSchedule: jobSchedule, Runs: runs}
+
+			if err := saveJobSchedules(apiName, jobSchedules); err != nil {
+				return nil, err
+			}
+			return &schema.CreateJobScheduleResponse{Message: "updated job schedule " + jobSchedule.Name}, nil
+		}
+	}
+
+	jobSchedule.CreatedTime = time.Now()
+	jobSchedules = append(jobSchedules, schema.JobScheduleStatus{JobSchedule: jobSchedule, Runs: []schema.JobScheduleRun{}})
+
+	if err := saveJobSchedules(apiName, jobSchedules); err != nil {
+		return nil, err
+	}
+	return &schema.CreateJobScheduleResponse{Message: "created job schedule " + jobSchedule.Name}, nil
+}
+
+// GetJobSchedules returns the API's schedules, including the current status of each run's job
+func GetJobSchedules(apiName string) ([]schema.JobScheduleStatus, error) {
+	_jobSchedulesMutex.Lock()
+	jobSchedules, err := readJobSchedules(apiName)
+	_jobSchedulesMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobSchedules {
+		for j := range jobSchedules[i].Runs {
+			run := &jobSchedules[i].Runs[j]
+			if run.JobID == "" {
+				continue
+			}
+
+			jobState, err := getJobState(spec.JobKey{APIName: apiName, ID: run.JobID})
+			if err != nil {
+				// the job's files may have been deleted
+				continue
+			}
+			jobStatus := jobState.Status
+			run.Status = &jobStatus
+		}
+	}
+
+	return jobSchedules, nil
+}
+
+func DeleteJobSchedule(apiName string, scheduleName string) error {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	jobSchedules, err := readJobSchedules(apiName)
+	if err != nil {
+		return err
+	}
+
+	remainingJobSchedules := make([]schema.JobScheduleStatus, 0, len(jobSchedules))
+	for _, jobSchedule := range jobSchedules {
+		if jobSchedule.Name != scheduleName {
+			remainingJobSchedules = append(remainingJobSchedules, jobSchedule)
+		}
+	}
+
+	if len(remainingJobSchedules) == len(jobSchedules) {
+		return ErrorJobScheduleNotFound(apiName, scheduleName)
+	}
+
+	return saveJobSchedules(apiName, remainingJobSchedules)
+}
+
+// RunJobSchedules submits the jobs of all schedules which have fired since they were last checked
+func RunJobSchedules() error {
+	virtualServices, err := config.K8s.ListVirtualServicesByLabel("apiKind", userconfig.BatchAPIKind.String())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	var errs []error
+	for _, virtualService := range virtualServices {
+		apiName := virtualService.Labels["apiName"]
+		if err := runJobSchedulesForAPI(apiName, now); err != nil {
+			errs = append(errs, errors.Wrap(err, "job schedules", apiName))
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func runJobSchedulesForAPI(apiName string, now time.Time) error {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	jobSchedules, err := readJobSchedules(apiName)
+	if err != nil {
+		return err
+	}
+	if len(jobSchedules) == 0 {
+		return nil
+	}
+
+	inProgressJobKeys, err := listAllInProgressJobKeysByAPI(apiName)
+	if err != nil {
+		return err
+	}
+	inProgressJobIDs := strset.New()
+	for _, jobKey := range inProgressJobKeys {
+		inProgressJobIDs.Add(jobKey.ID)
+	}
+
+	updated := false
+	for i := range jobSchedules {
+		jobSchedule := &jobSchedules[i]
+
+		scheduledTime := nextJobScheduleRun(jobSchedule, now)
+		if scheduledTime == nil {
+			continue
+		}
+
+		run := runJobSchedule(apiName, jobSchedule, *scheduledTime, inProgressJobIDs)
+		if run.JobID != "" {
+			inProgressJobIDs.Add(run.JobID)
+		}
+
+		jobSchedule.Runs = append([]schema.JobScheduleRun{run}, jobSchedule.Runs...)
+		if len(jobSchedule.Runs) > jobSchedule.HistoryLimit {
+			jobSchedule.Runs = jobSchedule.Runs[:jobSchedule.HistoryLimit]
+		}
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
+
+	return saveJobSchedules(apiName, jobSchedules)
+}
+
+// Returns the time that the schedule most recently fired if it hasn't been run since then, otherwise nil
+func nextJobScheduleRun(jobSchedule *schema.JobScheduleStatus, now time.Time) *time.Time {
+	cronSchedule, err := cron.ParseSchedule(jobSchedule.Schedule)
+	if err != nil {
+		return nil // the schedule was validated when it was created
+	}
+
+	since := now.Add(-_jobScheduleMissedRunGracePeriod)
+	// the schedule could otherwise fire immediately for the minute in which it was created (Last() truncates to the minute)
+	if firstMinute := jobSchedule.CreatedTime.Truncate(time.Minute).Add(time.Minute); firstMinute.After(since) {
+		since = firstMinute
+	}
+	if len(jobSchedule.Runs) > 0 {
+		if nextMinute := jobSchedule.Runs[0].ScheduledTime.Add(time.Minute); nextMinute.After(since) {
+			since = nextMinute
+		}
+	}
+
+	return cronSchedule.Last(now, since)
+}
+
+func runJobSchedule(apiName string, jobSchedule *schema.JobScheduleStatus, scheduledTime time.Time, inProgressJobIDs strset.Set) schema.JobScheduleRun {
+	run := schema.JobScheduleRun{ScheduledTime: scheduledTime}
+
+	var inProgressJobKeys []spec.JobKey
+	for _, prevRun := range jobSchedule.Runs {
+		if prevRun.JobID != "" && inProgressJobIDs.Has(prevRun.JobID) {
+			inProgressJobKeys = append(inProgressJobKeys, spec.JobKey{APIName: apiName, ID: prevRun.JobID})
+		}
+	}
+
+	if len(inProgressJobKeys) > 0 {
+		switch jobSchedule.ConcurrencyPolicy {
+		case schema.SkipConcurrencyPolicy:
+			run.Skipped = true
+			return run
+		case schema.ReplaceConcurrencyPolicy:
+			for _, jobKey := range inProgressJobKeys {
+				if err := StopJob(jobKey); err != nil {
+					run.Error = errors.Message(errors.Wrap(err, "unable to stop job "+jobKey.ID))
+					return run
+				}
+				inProgressJobIDs.Remove(jobKey.ID)
+			}
+		}
+	}
+
+	// validation may set defaults, so a copy of the template is submitted
+	submission := jobSchedule.Submission
+	jobSpec, err := SubmitJob(apiName, &submission)
+	if err != nil {
+		telemetry.Error(errors.Wrap(err, "job schedule", apiName, jobSchedule.Name))
+		run.Error = errors.Message(err)
+		return run
+	}
+
+	run.JobID = jobSpec.ID
+	writeToJobLogStream(jobSpec.JobKey, "submitted by job schedule "+jobSchedule.Name)
+
+	return run
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextJobScheduleRun(t *testing.T) {
+	createdTime := time.Date(2020, 9, 7, 8, 30, 20, 0, time.UTC)
+	jobSchedule := &schema.JobScheduleStatus{
+		JobSchedule: schema.JobSchedule{
+			Schedule:    "* * * * *",
+			CreatedTime: createdTime,
+		},
+	}
+
+	// the minute in which the schedule was created doesn't count as a run
+	require.Nil(t, nextJobScheduleRun(jobSchedule, createdTime.Add(10*time.Second)))
+
+	firstRun := time.Date(2020, 9, 7, 8, 31, 0, 0, time.UTC)
+	require.Equal(t, &firstRun, nextJobScheduleRun(jobSchedule, createdTime.Add(time.Minute)))
+
+	// the minute which was already run
+	jobSchedule.Runs = []schema.JobScheduleRun{{ScheduledTime: firstRun}}
+	require.Nil(t, nextJobScheduleRun(jobSchedule, firstRun.Add(30*time.Second)))
+
+	// the most recent missed run is submitted, as long as it is within the grace period
+	now := firstRun.Add(5*time.Minute + 30*time.Second)
+	expected := time.Date(2020, 9, 7, 8, 36, 0, 0, time.UTC)
+	require.Equal(t, &expected, nextJobScheduleRun(jobSchedule, now))
+
+	jobSchedule.Schedule = "0 * * * *"
+	require.Nil(t, nextJobScheduleRun(jobSchedule, time.Date(2020, 9, 7, 10, 30, 0, 0, time.UTC)))
+}
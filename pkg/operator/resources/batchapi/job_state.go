@@ -18,12 +18,9 @@ package batchapi
 
 import (
 	"fmt"
-	"path"
-	"path/filepath"
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
-	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
@@ -31,10 +28,6 @@ import (
 	kcore "k8s.io/api/core/v1"
 )
 
-const (
-	_averageFilesPerJobState = 10
-)
-
 type JobState struct {
 	spec.JobKey
 	Status         status.JobCode
@@ -54,272 +47,88 @@ func (j JobState) GetLastUpdated() time.Time {
 	return lastUpdated
 }
 
-// Doesn't assume only status files are present. The order below matters.
-func getStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
-	if _, ok := lastUpdatedMap[status.JobStopped.String()]; ok {
-		return status.JobStopped
-	}
-
-	if _, ok := lastUpdatedMap[status.JobWorkerOOM.String()]; ok {
-		return status.JobWorkerOOM
-	}
-
-	if _, ok := lastUpdatedMap[status.JobWorkerError.String()]; ok {
-		return status.JobWorkerError
-	}
-
-	if _, ok := lastUpdatedMap[status.JobEnqueueFailed.String()]; ok {
-		return status.JobEnqueueFailed
-	}
-
-	if _, ok := lastUpdatedMap[status.JobUnexpectedError.String()]; ok {
-		return status.JobUnexpectedError
-	}
-
-	if _, ok := lastUpdatedMap[status.JobCompletedWithFailures.String()]; ok {
-		return status.JobCompletedWithFailures
-	}
-
-	if _, ok := lastUpdatedMap[status.JobSucceeded.String()]; ok {
-		return status.JobSucceeded
-	}
-
-	if _, ok := lastUpdatedMap[status.JobRunning.String()]; ok {
-		return status.JobRunning
-	}
-
-	if _, ok := lastUpdatedMap[status.JobEnqueuing.String()]; ok {
-		return status.JobEnqueuing
-	}
-
-	return status.JobUnknown
-}
-
-func getJobState(jobKey spec.JobKey) (*JobState, error) {
-	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, jobKey.Prefix(), false, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get job state", jobKey.UserString())
-	}
-
-	if len(s3Objects) == 0 {
-		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
-	}
-
-	lastUpdatedMap := map[string]time.Time{}
-
-	for _, s3Object := range s3Objects {
-		lastUpdatedMap[filepath.Base(*s3Object.Key)] = *s3Object.LastModified
-	}
-
-	jobState := getJobStateFromFiles(jobKey, lastUpdatedMap)
-	return &jobState, nil
-}
-
-func getJobStateFromFiles(jobKey spec.JobKey, lastUpdatedFileMap map[string]time.Time) JobState {
-	statusCode := getStatusCode(lastUpdatedFileMap)
-
+func getJobStateFromLastUpdatedMap(jobKey spec.JobKey, statusCode status.JobCode, lastUpdatedMap map[string]time.Time) JobState {
 	var jobEndTime *time.Time
 	if statusCode.IsCompleted() {
-		if endTime, ok := lastUpdatedFileMap[statusCode.String()]; ok {
+		if endTime, ok := lastUpdatedMap[statusCode.String()]; ok {
 			jobEndTime = &endTime
 		}
 	}
 
 	return JobState{
 		JobKey:         jobKey,
-		LastUpdatedMap: lastUpdatedFileMap,
+		LastUpdatedMap: lastUpdatedMap,
 		Status:         statusCode,
 		EndTime:        jobEndTime,
 	}
 }
 
-func getMostRecentlySubmittedJobStates(apiName string, count int) ([]*JobState, error) {
-	// a single job state may include 5 files on average, overshoot the number of files needed
-	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, spec.BatchAPIJobPrefix(apiName), false, pointer.Int64(int64(count*_averageFilesPerJobState)))
-	if err != nil {
-		return nil, err
-	}
-
-	// job id -> file name -> last update timestamp
-	lastUpdatedMaps := map[string]map[string]time.Time{}
-
-	jobIDOrder := []string{}
-	for _, s3Object := range s3Objects {
-		fileName := filepath.Base(*s3Object.Key)
-		jobID := filepath.Base(filepath.Dir(*s3Object.Key))
-
-		if _, ok := lastUpdatedMaps[jobID]; !ok {
-			jobIDOrder = append(jobIDOrder, jobID)
-			lastUpdatedMaps[jobID] = map[string]time.Time{fileName: *s3Object.LastModified}
-		} else {
-			lastUpdatedMaps[jobID][fileName] = *s3Object.LastModified
-		}
-	}
-
-	jobStates := make([]*JobState, 0, count)
-
-	jobStateCount := 0
-	for _, jobID := range jobIDOrder {
-		jobState := getJobStateFromFiles(spec.JobKey{APIName: apiName, ID: jobID}, lastUpdatedMaps[jobID])
-		jobStates = append(jobStates, &jobState)
-
-		jobStateCount++
-		if jobStateCount == count {
-			break
-		}
-	}
-
-	return jobStates, nil
+func getJobState(jobKey spec.JobKey) (*JobState, error) {
+	return _jobStore.GetJobState(jobKey)
 }
 
-func setStatusForJob(jobKey spec.JobKey, jobStatus status.JobCode) error {
-	switch jobStatus {
-	case status.JobEnqueuing:
-		return setEnqueuingStatus(jobKey)
-	case status.JobRunning:
-		return setRunningStatus(jobKey)
-	case status.JobEnqueueFailed:
-		return setEnqueueFailedStatus(jobKey)
-	case status.JobCompletedWithFailures:
-		return setCompletedWithFailuresStatus(jobKey)
-	case status.JobSucceeded:
-		return setSucceededStatus(jobKey)
-	case status.JobUnexpectedError:
-		return setUnexpectedErrorStatus(jobKey)
-	case status.JobWorkerError:
-		return setWorkerErrorStatus(jobKey)
-	case status.JobWorkerOOM:
-		return setWorkerOOMStatus(jobKey)
-	case status.JobStopped:
-		return setStoppedStatus(jobKey)
-	}
-	return nil
+func getMostRecentlySubmittedJobStates(apiName string, count int) ([]*JobState, error) {
+	return _jobStore.ListJobStates(apiName, "", count)
 }
 
-func setEnqueuingStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobEnqueuing.String()))
+func setStatusForJob(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	err := _jobStore.SetStatus(jobKey, jobStatus)
 	if err != nil {
 		return err
 	}
 
-	err = uploadInProgressFile(jobKey)
-	if err != nil {
-		return err
+	if jobStatus.IsInProgress() {
+		return uploadInProgressFile(jobKey) // in progress file may already be there
 	}
+	return deleteInProgressFile(jobKey)
+}
 
-	return nil
+func setWaitingStatus(jobKey spec.JobKey) error {
+	return setStatusForJob(jobKey, status.JobWaiting)
 }
 
-func setRunningStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobRunning.String()))
-	if err != nil {
-		return err
-	}
+func setEnqueuingStatus(jobKey spec.JobKey) error {
+	return setStatusForJob(jobKey, status.JobEnqueuing)
+}
 
-	err = uploadInProgressFile(jobKey) // in progress file should already be there but just in case
-	if err != nil {
-		return err
-	}
+func setRunningStatus(jobKey spec.JobKey) error {
+	return setStatusForJob(jobKey, status.JobRunning)
+}
 
-	return nil
+func setPausedStatus(jobKey spec.JobKey) error {
+	return setStatusForJob(jobKey, status.JobPaused)
 }
 
 func setStoppedStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobStopped.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return setStatusForJob(jobKey, status.JobStopped)
 }
 
 func setSucceededStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobSucceeded.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return setStatusForJob(jobKey, status.JobSucceeded)
 }
 
 func setCompletedWithFailuresStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobCompletedWithFailures.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return setStatusForJob(jobKey, status.JobCompletedWithFailures)
 }
 
 func setWorkerErrorStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobWorkerError.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return setStatusForJob(jobKey, status.JobWorkerError)
 }
 
 func setWorkerOOMStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobWorkerOOM.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
+	return setStatusForJob(jobKey, status.JobWorkerOOM)
+}
 
-	return nil
+func setDependencyFailedStatus(jobKey spec.JobKey) error {
+	return setStatusForJob(jobKey, status.JobDependencyFailed)
 }
 
 func setEnqueueFailedStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobEnqueueFailed.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return setStatusForJob(jobKey, status.JobEnqueueFailed)
 }
 
 func setUnexpectedErrorStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobUnexpectedError.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return setStatusForJob(jobKey, status.JobUnexpectedError)
 }
 
 func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pods []kcore.Pod) (*status.JobStatus, error) {
@@ -377,7 +186,7 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 			jobStatus.TotalBatchCount = queueMetrics.TotalUserMessages()
 		}
 
-		if latestJobState.Status == status.JobRunning {
+		if latestJobState.Status == status.JobRunning || latestJobState.Status == status.JobPaused {
 			metrics, err := getRealTimeBatchMetrics(jobKey)
 			if err != nil {
 				return nil, err
@@ -399,6 +208,15 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 
 			workerCounts := getWorkerCountsForJob(*k8sJob, pods)
 			jobStatus.WorkerCounts = &workerCounts
+
+			// the throughput of a paused job would only reflect the time before it was paused
+			if latestJobState.Status == status.JobRunning {
+				jobProgress, err := getJobProgress(latestJobState, jobStatus.TotalBatchCount, *metrics, workerCounts)
+				if err != nil {
+					return nil, err
+				}
+				jobStatus.Progress = jobProgress
+			}
 		}
 	}
 
@@ -410,6 +228,16 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 		jobStatus.BatchMetrics = metrics
 	}
 
+	if jobSpec.Output != nil {
+		jobStatus.Output = &status.JobOutputStatus{
+			S3Path: jobSpec.Output.JobS3Path(jobKey.ID),
+			Format: jobSpec.Output.Format,
+		}
+		if jobStatus.BatchMetrics != nil {
+			jobStatus.Output.ItemsWritten = jobStatus.BatchMetrics.OutputItems
+		}
+	}
+
 	return &jobStatus, nil
 }
 
@@ -419,6 +247,13 @@ func GetJobStatus(jobKey spec.JobKey) (*status.JobStatus, error) {
 		return nil, err
 	}
 
+	return getJobStatusWithK8sResources(jobState)
+}
+
+// fetches the job's kubernetes job and pods, which are needed to get the status of a job that is in progress
+func getJobStatusWithK8sResources(jobState *JobState) (*status.JobStatus, error) {
+	jobKey := jobState.JobKey
+
 	k8sJob, err := config.K8s.GetJob(jobKey.K8sName())
 	if err != nil {
 		return nil, err
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"os"
+
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	S3JobStoreBackend        = "s3"
+	ConfigMapJobStoreBackend = "configmap"
+)
+
+var JobStoreBackends = []string{S3JobStoreBackend, ConfigMapJobStoreBackend}
+
+// JobStore persists the status of batch jobs; the job's spec and other files are always stored in the cluster's bucket
+type JobStore interface {
+	// returns ErrJobNotFound if the job doesn't exist
+	GetJobState(jobKey spec.JobKey) (*JobState, error)
+	// returns up to count of the API's jobs which were submitted before startAfterJobID (or all jobs if it's empty), most recently submitted first
+	ListJobStates(apiName string, startAfterJobID string, count int) ([]*JobState, error)
+	// returns ErrInvalidJobStatusTransition if the job's current status can't transition to the new status
+	SetStatus(jobKey spec.JobKey, jobCode status.JobCode) error
+	UpdateLiveness(jobKey spec.JobKey) error
+	DeleteJobState(jobKey spec.JobKey) error
+	DeleteJobStates(apiName string) error
+}
+
+var _jobStore JobStore = &s3JobStore{}
+
+// InitJobStore selects the job store backend (the CORTEX_BATCH_JOB_STORE environment variable, which defaults to s3); jobs which were submitted with a different backend are not migrated
+func InitJobStore() error {
+	switch backend := os.Getenv("CORTEX_BATCH_JOB_STORE"); backend {
+	case "", S3JobStoreBackend:
+		_jobStore = &s3JobStore{}
+	case ConfigMapJobStoreBackend:
+		_jobStore = &configMapJobStore{}
+	default:
+		return ErrorInvalidJobStoreBackend(backend)
+	}
+	return nil
+}
+
+func validateJobStatusTransition(jobKey spec.JobKey, currentStatus status.JobCode, newStatus status.JobCode) error {
+	if !currentStatus.CanTransitionTo(newStatus) {
+		return ErrorInvalidJobStatusTransition(jobKey, currentStatus, newStatus)
+	}
+	return nil
+}
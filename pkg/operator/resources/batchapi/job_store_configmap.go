@@ -0,0 +1,207 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	_jobStateConfigMapKey    = "job_state.json"
+	_jobStateConflictRetries = 5
+)
+
+// configMapJobStore keeps a single state document per job in a config map, which is updated with optimistic concurrency (the update is rejected if the config map was modified since it was read), so status transitions are validated against the latest status
+type configMapJobStore struct{}
+
+type jobStateDocument struct {
+	Status      status.JobCode       `json:"status"`
+	LastUpdated map[string]time.Time `json:"last_updated"` // the time that each status (and the enqueuing liveness) was last set
+}
+
+func jobStateConfigMapName(jobKey spec.JobKey) string {
+	return "job-state-" + jobKey.K8sName()
+}
+
+func jobStateLabels(apiName string) map[string]string {
+	return map[string]string{
+		"apiName":  apiName,
+		"jobState": "true",
+	}
+}
+
+func parseJobStateDocument(jobKey spec.JobKey, data map[string]string) (*jobStateDocument, error) {
+	document := jobStateDocument{}
+	if err := json.Unmarshal([]byte(data[_jobStateConfigMapKey]), &document); err != nil {
+		return nil, errors.Wrap(err, "failed to parse job state", jobKey.UserString())
+	}
+	if document.LastUpdated == nil {
+		document.LastUpdated = map[string]time.Time{}
+	}
+	return &document, nil
+}
+
+func (store *configMapJobStore) GetJobState(jobKey spec.JobKey) (*JobState, error) {
+	configMap, err := config.K8s.GetConfigMap(jobStateConfigMapName(jobKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get job state", jobKey.UserString())
+	}
+	if configMap == nil {
+		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
+	}
+
+	document, err := parseJobStateDocument(jobKey, configMap.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	jobState := getJobStateFromLastUpdatedMap(jobKey, document.Status, document.LastUpdated)
+	return &jobState, nil
+}
+
+func (store *configMapJobStore) ListJobStates(apiName string, startAfterJobID string, count int) ([]*JobState, error) {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(jobStateLabels(apiName))
+	if err != nil {
+		return nil, err
+	}
+
+	// job ids are monotonically decreasing, so the most recently submitted jobs are first
+	sort.Slice(configMaps, func(i, j int) bool {
+		return configMaps[i].Labels["jobID"] < configMaps[j].Labels["jobID"]
+	})
+
+	jobStates := make([]*JobState, 0, count)
+	for _, configMap := range configMaps {
+		if len(jobStates) == count {
+			break
+		}
+		if startAfterJobID != "" && configMap.Labels["jobID"] <= startAfterJobID {
+			continue
+		}
+
+		jobKey := spec.JobKey{APIName: apiName, ID: configMap.Labels["jobID"]}
+		document, err := parseJobStateDocument(jobKey, configMap.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		jobState := getJobStateFromLastUpdatedMap(jobKey, document.Status, document.LastUpdated)
+		jobStates = append(jobStates, &jobState)
+	}
+
+	return jobStates, nil
+}
+
+func (store *configMapJobStore) SetStatus(jobKey spec.JobKey, jobCode status.JobCode) error {
+	return store.updateJobState(jobKey, func(document *jobStateDocument) error {
+		if err := validateJobStatusTransition(jobKey, document.Status, jobCode); err != nil {
+			return err
+		}
+		document.Status = jobCode
+		document.LastUpdated[jobCode.String()] = time.Now()
+		return nil
+	})
+}
+
+func (store *configMapJobStore) UpdateLiveness(jobKey spec.JobKey) error {
+	return store.updateJobState(jobKey, func(document *jobStateDocument) error {
+		if document.Status == status.JobUnknown {
+			return ErrorJobNotFound(jobKey)
+		}
+		document.LastUpdated[_enqueuingLivenessFile] = time.Now()
+		return nil
+	})
+}
+
+func (store *configMapJobStore) DeleteJobState(jobKey spec.JobKey) error {
+	_, err := config.K8s.DeleteConfigMap(jobStateConfigMapName(jobKey))
+	return err
+}
+
+func (store *configMapJobStore) DeleteJobStates(apiName string) error {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(jobStateLabels(apiName))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, configMap := range configMaps {
+		_, err := config.K8s.DeleteConfigMap(configMap.Name)
+		errs = append(errs, err)
+	}
+	return errors.FirstError(errs...)
+}
+
+// updateJobState applies the update to the latest state document (the job's state is created if it doesn't exist yet), and retries if the document was modified concurrently
+func (store *configMapJobStore) updateJobState(jobKey spec.JobKey, update func(document *jobStateDocument) error) error {
+	name := jobStateConfigMapName(jobKey)
+
+	for attempt := 0; attempt < _jobStateConflictRetries; attempt++ {
+		configMap, err := config.K8s.GetConfigMap(name)
+		if err != nil {
+			return err
+		}
+
+		document := &jobStateDocument{Status: status.JobUnknown, LastUpdated: map[string]time.Time{}}
+		if configMap != nil {
+			document, err = parseJobStateDocument(jobKey, configMap.Data)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := update(document); err != nil {
+			return err
+		}
+
+		documentStr, err := json.MarshalJSONStr(document)
+		if err != nil {
+			return err
+		}
+		data := map[string]string{_jobStateConfigMapKey: documentStr}
+
+		var updated bool
+		if configMap == nil {
+			labels := jobStateLabels(jobKey.APIName)
+			labels["jobID"] = jobKey.ID
+			updated, err = config.K8s.CreateConfigMapIfNotExists(k8s.ConfigMap(&k8s.ConfigMapSpec{
+				Name:   name,
+				Data:   data,
+				Labels: labels,
+			}))
+		} else {
+			configMap.Data = data
+			updated, err = config.K8s.UpdateConfigMapIfUnchanged(configMap)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to update job state", jobKey.UserString())
+		}
+		if updated {
+			return nil
+		}
+	}
+
+	return ErrorJobStateConflict(jobKey)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	_averageFilesPerJobState = 10
+)
+
+// s3JobStore marks each status that a job has reached with an empty file in the job's prefix, and infers the job's status from the files that exist; since s3 has no conditional writes, status transitions are validated but concurrent transitions are not detected
+type s3JobStore struct{}
+
+// Doesn't assume only status files are present. The order below matters.
+func getStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
+	if _, ok := lastUpdatedMap[status.JobStopped.String()]; ok {
+		return status.JobStopped
+	}
+
+	if _, ok := lastUpdatedMap[status.JobWorkerOOM.String()]; ok {
+		return status.JobWorkerOOM
+	}
+
+	if _, ok := lastUpdatedMap[status.JobWorkerError.String()]; ok {
+		return status.JobWorkerError
+	}
+
+	if _, ok := lastUpdatedMap[status.JobDependencyFailed.String()]; ok {
+		return status.JobDependencyFailed
+	}
+
+	if _, ok := lastUpdatedMap[status.JobEnqueueFailed.String()]; ok {
+		return status.JobEnqueueFailed
+	}
+
+	if _, ok := lastUpdatedMap[status.JobUnexpectedError.String()]; ok {
+		return status.JobUnexpectedError
+	}
+
+	if _, ok := lastUpdatedMap[status.JobCompletedWithFailures.String()]; ok {
+		return status.JobCompletedWithFailures
+	}
+
+	if _, ok := lastUpdatedMap[status.JobSucceeded.String()]; ok {
+		return status.JobSucceeded
+	}
+
+	if _, ok := lastUpdatedMap[status.JobPaused.String()]; ok {
+		return status.JobPaused
+	}
+
+	if _, ok := lastUpdatedMap[status.JobRunning.String()]; ok {
+		return status.JobRunning
+	}
+
+	if _, ok := lastUpdatedMap[status.JobEnqueuing.String()]; ok {
+		return status.JobEnqueuing
+	}
+
+	if _, ok := lastUpdatedMap[status.JobWaiting.String()]; ok {
+		return status.JobWaiting
+	}
+
+	return status.JobUnknown
+}
+
+func (store *s3JobStore) GetJobState(jobKey spec.JobKey) (*JobState, error) {
+	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, jobKey.Prefix(), false, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get job state", jobKey.UserString())
+	}
+
+	if len(s3Objects) == 0 {
+		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
+	}
+
+	lastUpdatedMap := map[string]time.Time{}
+
+	for _, s3Object := range s3Objects {
+		lastUpdatedMap[filepath.Base(*s3Object.Key)] = *s3Object.LastModified
+	}
+
+	jobState := getJobStateFromLastUpdatedMap(jobKey, getStatusCode(lastUpdatedMap), lastUpdatedMap)
+	return &jobState, nil
+}
+
+func (store *s3JobStore) ListJobStates(apiName string, startAfterJobID string, count int) ([]*JobState, error) {
+	prefix := s.EnsureSuffix(spec.BatchAPIJobPrefix(apiName), "/")
+
+	// a single job state may include 5 files on average, overshoot the number of files needed
+	maxResults := int64(count * _averageFilesPerJobState)
+	s3Objects, err := config.AWS.ListS3PrefixStartAfter(config.Cluster.Bucket, prefix, jobFilesStartAfterKey(prefix, startAfterJobID), false, &maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	jobIDOrder, lastUpdatedMaps := groupJobFiles(s3Objects, int64(len(s3Objects)) >= maxResults)
+
+	jobStates := make([]*JobState, 0, count)
+
+	jobStateCount := 0
+	for _, jobID := range jobIDOrder {
+		lastUpdatedMap := lastUpdatedMaps[jobID]
+		jobState := getJobStateFromLastUpdatedMap(spec.JobKey{APIName: apiName, ID: jobID}, getStatusCode(lastUpdatedMap), lastUpdatedMap)
+		jobStates = append(jobStates, &jobState)
+
+		jobStateCount++
+		if jobStateCount == count {
+			break
+		}
+	}
+
+	return jobStates, nil
+}
+
+// returns the key after which the files of the jobs that were submitted before startAfterJobID are listed ("" lists all jobs)
+func jobFilesStartAfterKey(prefix string, startAfterJobID string) string {
+	if startAfterJobID == "" {
+		return ""
+	}
+	// the keys of a job's files start with "<job id>/", and "/" sorts before "0" (job ids all have the same length, so no other job id starts with this one)
+	return prefix + startAfterJobID + "0"
+}
+
+// groups the listed files by job id (in the order in which the jobs were listed) into job id -> file name -> last update timestamp;
+// if the listing was truncated, the last job's files may not all have been listed, so it is left out (unless it is the only job)
+func groupJobFiles(s3Objects []*s3.Object, isTruncated bool) ([]string, map[string]map[string]time.Time) {
+	lastUpdatedMaps := map[string]map[string]time.Time{}
+
+	jobIDOrder := []string{}
+	for _, s3Object := range s3Objects {
+		fileName := filepath.Base(*s3Object.Key)
+		jobID := filepath.Base(filepath.Dir(*s3Object.Key))
+
+		if _, ok := lastUpdatedMaps[jobID]; !ok {
+			jobIDOrder = append(jobIDOrder, jobID)
+			lastUpdatedMaps[jobID] = map[string]time.Time{fileName: *s3Object.LastModified}
+		} else {
+			lastUpdatedMaps[jobID][fileName] = *s3Object.LastModified
+		}
+	}
+
+	if isTruncated && len(jobIDOrder) > 1 {
+		lastJobID := jobIDOrder[len(jobIDOrder)-1]
+		jobIDOrder = jobIDOrder[:len(jobIDOrder)-1]
+		delete(lastUpdatedMaps, lastJobID)
+	}
+
+	return jobIDOrder, lastUpdatedMaps
+}
+
+func (store *s3JobStore) SetStatus(jobKey spec.JobKey, jobCode status.JobCode) error {
+	currentStatus := status.JobUnknown
+	jobState, err := store.GetJobState(jobKey)
+	if err != nil {
+		if errors.GetKind(err) != ErrJobNotFound {
+			return err
+		}
+	} else {
+		currentStatus = jobState.Status
+	}
+
+	if err := validateJobStatusTransition(jobKey, currentStatus, jobCode); err != nil {
+		return err
+	}
+
+	if err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), jobCode.String())); err != nil {
+		return err
+	}
+
+	// a job can be paused and resumed multiple times, so the paused file is removed when the job is resumed
+	if currentStatus == status.JobPaused && jobCode == status.JobRunning {
+		return config.AWS.DeleteS3File(config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobPaused.String()))
+	}
+
+	return nil
+}
+
+func (store *s3JobStore) UpdateLiveness(jobKey spec.JobKey) error {
+	return config.AWS.UploadJSONToS3(time.Now(), config.Cluster.Bucket, path.Join(jobKey.Prefix(), _enqueuingLivenessFile))
+}
+
+// the status files are deleted along with the job's other files
+func (store *s3JobStore) DeleteJobState(jobKey spec.JobKey) error {
+	return nil
+}
+
+// the status files are deleted along with the API's other job files
+func (store *s3JobStore) DeleteJobStates(apiName string) error {
+	return nil
+}
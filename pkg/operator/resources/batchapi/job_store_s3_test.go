@@ -0,0 +1,138 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobFilesStartAfterKey(t *testing.T) {
+	prefix := "apis/test/jobs/"
+	submittedTime := time.Date(2020, 9, 7, 8, 30, 0, 0, time.UTC)
+
+	// most recently submitted first
+	jobIDs := []string{
+		spec.MonotonicallyDecreasingIDForTime(submittedTime.Add(time.Second)),
+		spec.MonotonicallyDecreasingIDForTime(submittedTime),
+		spec.MonotonicallyDecreasingIDForTime(submittedTime.Add(-time.Nanosecond)),
+		spec.MonotonicallyDecreasingIDForTime(submittedTime.Add(-time.Hour)),
+	}
+
+	var keys []string
+	for _, jobID := range jobIDs {
+		for _, fileName := range []string{"spec.json", "enqueuing", "running", "succeeded"} {
+			keys = append(keys, prefix+jobID+"/"+fileName)
+		}
+	}
+	// s3 lists keys in ascending utf-8 binary order
+	sort.Strings(keys)
+
+	require.Equal(t, "", jobFilesStartAfterKey(prefix, ""))
+
+	for i, startAfterJobID := range jobIDs {
+		startAfter := jobFilesStartAfterKey(prefix, startAfterJobID)
+
+		listedJobIDs := []string{}
+		for _, key := range keys {
+			if key <= startAfter {
+				continue
+			}
+			jobID := strings.Split(strings.TrimPrefix(key, prefix), "/")[0]
+			if len(listedJobIDs) == 0 || listedJobIDs[len(listedJobIDs)-1] != jobID {
+				listedJobIDs = append(listedJobIDs, jobID)
+			}
+		}
+
+		require.Equal(t, jobIDs[i+1:], listedJobIDs)
+	}
+}
+
+func TestGroupJobFiles(t *testing.T) {
+	lastModified := time.Date(2020, 9, 7, 8, 30, 0, 0, time.UTC)
+
+	var s3Objects []*s3.Object
+	for _, key := range []string{"jobs/a/spec.json", "jobs/a/running", "jobs/b/spec.json", "jobs/c/spec.json", "jobs/c/enqueuing"} {
+		s3Objects = append(s3Objects, &s3.Object{Key: aws.String(key), LastModified: aws.Time(lastModified)})
+	}
+
+	jobIDs, lastUpdatedMaps := groupJobFiles(s3Objects, false)
+	require.Equal(t, []string{"a", "b", "c"}, jobIDs)
+	require.Len(t, lastUpdatedMaps, 3)
+	require.Equal(t, map[string]time.Time{"spec.json": lastModified, "running": lastModified}, lastUpdatedMaps["a"])
+
+	// the last job's files may not all have been listed
+	jobIDs, lastUpdatedMaps = groupJobFiles(s3Objects, true)
+	require.Equal(t, []string{"a", "b"}, jobIDs)
+	require.Len(t, lastUpdatedMaps, 2)
+
+	// a job is always returned so that the listing can make progress
+	jobIDs, lastUpdatedMaps = groupJobFiles(s3Objects[:2], true)
+	require.Equal(t, []string{"a"}, jobIDs)
+	require.Len(t, lastUpdatedMaps, 1)
+
+	jobIDs, lastUpdatedMaps = groupJobFiles(nil, true)
+	require.Empty(t, jobIDs)
+	require.Empty(t, lastUpdatedMaps)
+}
+
+func TestGetStatusCode(t *testing.T) {
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name     string
+		statuses []status.JobCode
+		expected status.JobCode
+	}{
+		{
+			name:     "no statuses",
+			statuses: nil,
+			expected: status.JobUnknown,
+		},
+		{
+			name:     "running",
+			statuses: []status.JobCode{status.JobEnqueuing, status.JobRunning},
+			expected: status.JobRunning,
+		},
+		{
+			name:     "paused",
+			statuses: []status.JobCode{status.JobEnqueuing, status.JobRunning, status.JobPaused},
+			expected: status.JobPaused,
+		},
+		{
+			name:     "stopped while paused",
+			statuses: []status.JobCode{status.JobEnqueuing, status.JobRunning, status.JobPaused, status.JobStopped},
+			expected: status.JobStopped,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lastUpdatedMap := map[string]time.Time{}
+			for _, jobCode := range tc.statuses {
+				lastUpdatedMap[jobCode.String()] = now
+			}
+			require.Equal(t, tc.expected, getStatusCode(lastUpdatedMap))
+		})
+	}
+}
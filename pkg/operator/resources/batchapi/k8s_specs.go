@@ -78,7 +78,8 @@ func pythonPredictorJobSpec(api *spec.API, job *spec.Job) (*kbatch.Job, error) {
 				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
 			},
 			K8sPodSpec: kcore.PodSpec{
-				RestartPolicy: "Never",
+				RestartPolicy:                 "Never",
+				TerminationGracePeriodSeconds: workerTerminationGracePeriod(job),
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
@@ -127,7 +128,8 @@ func tensorFlowPredictorJobSpec(api *spec.API, job *spec.Job) (*kbatch.Job, erro
 				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
 			},
 			K8sPodSpec: kcore.PodSpec{
-				RestartPolicy: "Never",
+				RestartPolicy:                 "Never",
+				TerminationGracePeriodSeconds: workerTerminationGracePeriod(job),
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
@@ -177,7 +179,8 @@ func onnxPredictorJobSpec(api *spec.API, job *spec.Job) (*kbatch.Job, error) {
 				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
 			},
 			K8sPodSpec: kcore.PodSpec{
-				RestartPolicy: "Never",
+				RestartPolicy:                 "Never",
+				TerminationGracePeriodSeconds: workerTerminationGracePeriod(job),
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
@@ -226,3 +229,11 @@ func applyK8sResources(api *spec.API, prevVirtualService *istioclientnetworking.
 	_, err := config.K8s.UpdateVirtualService(prevVirtualService, newVirtualService)
 	return err
 }
+
+// workers which are terminated (when their job is paused or resized) finish their current batch before exiting, which may take up to the job's timeout
+func workerTerminationGracePeriod(job *spec.Job) *int64 {
+	if job.Timeout == nil {
+		return nil
+	}
+	return pointer.Int64(int64(*job.Timeout))
+}
@@ -55,7 +55,7 @@ func ManageJobResources() error {
 		inProgressIDMap[jobKey.ID] = jobKey
 	}
 
-	queues, err := listQueueURLsForAllAPIs()
+	queues, deadLetterQueues, err := listQueueURLsForAllAPIs()
 	if err != nil {
 		return err
 	}
@@ -110,6 +110,16 @@ func ManageJobResources() error {
 			}
 		}
 
+		// waiting jobs don't have workers yet
+		if jobState.Status == status.JobWaiting {
+			err := checkJobDependencies(jobKey)
+			if err != nil {
+				telemetry.Error(err)
+				errors.PrintError(err)
+			}
+			continue
+		}
+
 		newStatusCode, msg, err := reconcileInProgressJob(jobState, queueURL, k8sJob)
 		if err != nil {
 			telemetry.Error(err)
@@ -129,6 +139,12 @@ func ManageJobResources() error {
 			}
 		}
 
+		// paused jobs have no workers, so their batches remain in the queue until they are resumed
+		if newStatusCode == status.JobPaused {
+			jobsToDelete.Remove(jobKey.ID)
+			continue
+		}
+
 		err = checkIfJobCompleted(jobKey, *queueURL, k8sJob)
 		if err != nil {
 			telemetry.Error(err)
@@ -177,6 +193,33 @@ func ManageJobResources() error {
 		}
 	}
 
+	// dead letter queues are normally deleted along with the job's other runtime resources, but may be left behind if that failed
+	for _, deadLetterQueueURL := range deadLetterQueues {
+		jobKey := jobKeyFromQueueURL(deadLetterQueueURL)
+		if inProgressJobIDSet.Has(jobKey.ID) || queueJobIDSet.Has(jobKey.ID) {
+			continue
+		}
+
+		attributes, err := config.AWS.GetAllQueueAttributes(deadLetterQueueURL)
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+			continue
+		}
+
+		// the dead letter queue is created before the job's queue and in progress file
+		parsedSeconds, ok := s.ParseInt64(attributes["CreatedTimestamp"])
+		if ok && time.Now().Sub(time.Unix(parsedSeconds, 0)) <= _doesQueueExistGracePeriod {
+			continue
+		}
+
+		err = saveAndDeleteDeadLetterQueue(jobKey)
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+		}
+	}
+
 	// Clear old jobs to delete if they are no longer considered to in progress
 	for jobID := range jobsToDelete {
 		if !inProgressJobIDSet.Has(jobID) {
@@ -187,7 +230,7 @@ func ManageJobResources() error {
 	return nil
 }
 
-// verifies that queue exists for an in progress job and k8s job exists for a job in running status, if verification fails return the a job code to reflect the state
+// verifies that queue exists for an in progress job and k8s job exists for a job in running or paused status, if verification fails return the a job code to reflect the state
 func reconcileInProgressJob(jobState *JobState, queueURL *string, k8sJob *kbatch.Job) (status.JobCode, string, error) {
 	jobKey := jobState.JobKey
 
@@ -213,7 +256,7 @@ func reconcileInProgressJob(jobState *JobState, queueURL *string, k8sJob *kbatch
 		return status.JobEnqueueFailed, fmt.Sprintf("terminating job %s; enqueuing liveness check failed", jobKey.UserString()), nil
 	}
 
-	if jobState.Status == status.JobRunning {
+	if jobState.Status == status.JobRunning || jobState.Status == status.JobPaused {
 		if time.Now().Sub(jobState.LastUpdatedMap[status.JobRunning.String()]) <= _k8sJobExistenceGracePeriod {
 			return jobState.Status, "", nil
 		}
@@ -227,7 +270,7 @@ func reconcileInProgressJob(jobState *JobState, queueURL *string, k8sJob *kbatch
 }
 
 func checkIfJobCompleted(jobKey spec.JobKey, queueURL string, k8sJob *kbatch.Job) error {
-	if int(k8sJob.Status.Failed) > 0 {
+	if hasWorkerFailures(k8sJob) {
 		return investigateJobFailure(jobKey, k8sJob)
 	}
 
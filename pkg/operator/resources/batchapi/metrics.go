@@ -125,6 +125,8 @@ func extractJobStats(metricsDataResults []*cloudwatch.MetricDataResult) (*metric
 			jobStats.Succeeded = slices.Float64PtrSumInt(metricData.Values...)
 		case *metricData.Label == "Failed":
 			jobStats.Failed = slices.Float64PtrSumInt(metricData.Values...)
+		case *metricData.Label == "OutputItems":
+			jobStats.OutputItems = slices.Float64PtrSumInt(metricData.Values...)
 		case *metricData.Label == "AverageTimePerBatch":
 			latencyAvgs = metricData.Values
 		case *metricData.Label == "Total":
@@ -202,6 +204,19 @@ func batchMetricsDef(jobKey *spec.JobKey, period int64) []*cloudwatch.MetricData
 				Period: aws.Int64(period),
 			},
 		},
+		{
+			Id:    aws.String("output_items"),
+			Label: aws.String("OutputItems"),
+			MetricStat: &cloudwatch.MetricStat{
+				Metric: &cloudwatch.Metric{
+					Namespace:  aws.String(config.Cluster.LogGroup),
+					MetricName: aws.String("OutputItems"),
+					Dimensions: getJobDimensionsCounter(jobKey),
+				},
+				Stat:   aws.String("Sum"),
+				Period: aws.Int64(period),
+			},
+		},
 		{
 			Id:    aws.String("average_time_per_batch"),
 			Label: aws.String("AverageTimePerBatch"),
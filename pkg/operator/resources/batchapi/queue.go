@@ -17,8 +17,10 @@ limitations under the License.
 package batchapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -29,6 +31,17 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
+const (
+	_deadLetterQueueSuffix = "-dlq.fifo"
+	_maxRetriesLimit       = 999
+	// seconds
+	_defaultVisibilityTimeout = 120
+	_minVisibilityTimeout     = 10
+	_maxVisibilityTimeout     = 12 * 60 * 60
+	// the retention period of a message in a dead letter queue is based on when it was originally enqueued, so the maximum is used
+	_deadLetterQueueRetentionPeriod = 14 * 24 * time.Hour
+)
+
 func apiQueueNamePrefix(apiName string) string {
 	return config.Cluster.SQSNamePrefix() + apiName + "-"
 }
@@ -38,6 +51,15 @@ func getJobQueueName(jobKey spec.JobKey) string {
 	return apiQueueNamePrefix(jobKey.APIName) + jobKey.ID + ".fifo"
 }
 
+// DeadLetterQueueName is <hash of cluster name>-<api_name>-<job_id>-dlq.fifo
+func getJobDeadLetterQueueName(jobKey spec.JobKey) string {
+	return apiQueueNamePrefix(jobKey.APIName) + jobKey.ID + _deadLetterQueueSuffix
+}
+
+func isDeadLetterQueueURL(queueURL string) bool {
+	return strings.HasSuffix(queueURL, _deadLetterQueueSuffix)
+}
+
 func getJobQueueURL(jobKey spec.JobKey) (string, error) {
 	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
 	if err != nil {
@@ -47,9 +69,19 @@ func getJobQueueURL(jobKey spec.JobKey) (string, error) {
 	return fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", config.AWS.Region, operatorAccountID, getJobQueueName(jobKey)), nil
 }
 
+func getJobDeadLetterQueueURL(jobKey spec.JobKey) (string, error) {
+	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to construct dead letter queue url", "unable to get account id")
+	}
+
+	return fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", config.AWS.Region, operatorAccountID, getJobDeadLetterQueueName(jobKey)), nil
+}
+
+// works for both job queues and dead letter queues
 func jobKeyFromQueueURL(queueURL string) spec.JobKey {
 	split := strings.Split(queueURL, "/")
-	queueName := split[len(split)-1]
+	queueName := strings.TrimSuffix(split[len(split)-1], _deadLetterQueueSuffix)
 
 	dashSplit := strings.Split(queueName, "-")
 
@@ -61,41 +93,101 @@ func jobKeyFromQueueURL(queueURL string) spec.JobKey {
 	return spec.JobKey{APIName: apiName, ID: jobID}
 }
 
-func createFIFOQueue(jobKey spec.JobKey, tags map[string]string) (string, error) {
+// if MaxRetries is specified, a dead letter queue is created for the job, to which batches are moved once they have failed MaxRetries+1 times
+func createFIFOQueue(jobKey spec.JobKey, jobConfig spec.RuntimeJobConfig, tags map[string]string) (string, error) {
 	for key, value := range config.Cluster.Tags {
 		tags[key] = value
 	}
 
 	queueName := getJobQueueName(jobKey)
 
+	attributes := map[string]*string{
+		"FifoQueue":         aws.String("true"),
+		"VisibilityTimeout": aws.String(s.Int(*jobConfig.Timeout)),
+	}
+
+	if jobConfig.MaxRetries != nil {
+		deadLetterQueueARN, err := createDeadLetterQueue(jobKey, tags)
+		if err != nil {
+			return "", err
+		}
+
+		redrivePolicy, err := json.Marshal(map[string]string{
+			"deadLetterTargetArn": deadLetterQueueARN,
+			"maxReceiveCount":     s.Int(*jobConfig.MaxRetries + 1),
+		})
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		attributes["RedrivePolicy"] = aws.String(string(redrivePolicy))
+	}
+
 	output, err := config.AWS.SQS().CreateQueue(
+		&sqs.CreateQueueInput{
+			Attributes: attributes,
+			QueueName:  aws.String(queueName),
+			Tags:       aws.StringMap(tags),
+		},
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create sqs queue", queueName)
+	}
+
+	return *output.QueueUrl, nil
+}
+
+// returns the arn of the dead letter queue
+func createDeadLetterQueue(jobKey spec.JobKey, tags map[string]string) (string, error) {
+	queueName := getJobDeadLetterQueueName(jobKey)
+
+	_, err := config.AWS.SQS().CreateQueue(
 		&sqs.CreateQueueInput{
 			Attributes: map[string]*string{
-				"FifoQueue":         aws.String("true"),
-				"VisibilityTimeout": aws.String("120"),
+				"FifoQueue":              aws.String("true"),
+				"MessageRetentionPeriod": aws.String(s.Int64(int64(_deadLetterQueueRetentionPeriod.Seconds()))),
 			},
 			QueueName: aws.String(queueName),
 			Tags:      aws.StringMap(tags),
 		},
 	)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create sqs queue", queueName)
+		return "", errors.Wrap(err, "failed to create sqs dead letter queue", queueName)
 	}
 
-	return *output.QueueUrl, nil
+	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to construct dead letter queue arn", "unable to get account id")
+	}
+
+	return fmt.Sprintf("arn:aws:sqs:%s:%s:%s", config.AWS.Region, operatorAccountID, queueName), nil
 }
 
 func doesQueueExist(jobKey spec.JobKey) (bool, error) {
 	return config.AWS.DoesQueueExist(getJobQueueName(jobKey))
 }
 
-func listQueueURLsForAllAPIs() ([]string, error) {
-	queueURLs, err := config.AWS.ListQueuesByQueueNamePrefix(config.Cluster.SQSNamePrefix())
+func doesDeadLetterQueueExist(jobKey spec.JobKey) (bool, error) {
+	return config.AWS.DoesQueueExist(getJobDeadLetterQueueName(jobKey))
+}
+
+// returns the job queues and the dead letter queues separately
+func listQueueURLsForAllAPIs() ([]string, []string, error) {
+	allQueueURLs, err := config.AWS.ListQueuesByQueueNamePrefix(config.Cluster.SQSNamePrefix())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	var queueURLs []string
+	var deadLetterQueueURLs []string
+	for _, queueURL := range allQueueURLs {
+		if isDeadLetterQueueURL(queueURL) {
+			deadLetterQueueURLs = append(deadLetterQueueURLs, queueURL)
+		} else {
+			queueURLs = append(queueURLs, queueURL)
+		}
 	}
 
-	return queueURLs, nil
+	return queueURLs, deadLetterQueueURLs, nil
 }
 
 func deleteQueueByJobKey(jobKey spec.JobKey) error {
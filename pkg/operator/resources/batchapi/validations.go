@@ -18,16 +18,27 @@ package batchapi
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/gobwas/glob"
 )
 
+const (
+	_maxJobLabels           = 50
+	_maxJobLabelKeyLength   = 63
+	_maxJobLabelValueLength = 256
+)
+
 func validateJobSubmissionSchema(submission *schema.JobSubmission) error {
 	providedKeys := []string{}
 	if submission.ItemList != nil {
@@ -39,9 +50,12 @@ func validateJobSubmissionSchema(submission *schema.JobSubmission) error {
 	if submission.DelimitedFiles != nil {
 		providedKeys = append(providedKeys, schema.DelimitedFilesKey)
 	}
+	if submission.CSVFiles != nil {
+		providedKeys = append(providedKeys, schema.CSVFilesKey)
+	}
 
 	if len(providedKeys) == 0 {
-		return ErrorSpecifyExactlyOneKey(schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey)
+		return ErrorSpecifyExactlyOneKey(schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey, schema.CSVFilesKey)
 	}
 
 	if len(providedKeys) > 1 {
@@ -76,10 +90,79 @@ func validateJobSubmissionSchema(submission *schema.JobSubmission) error {
 		}
 	}
 
+	if submission.CSVFiles != nil {
+		if submission.CSVFiles.BatchSize < 1 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.CSVFiles.BatchSize, 1), schema.CSVFilesKey, schema.BatchSizeKey)
+		}
+
+		if submission.CSVFiles.Delimiter == "" {
+			submission.CSVFiles.Delimiter = _defaultCSVDelimiter
+		}
+		if !isValidCSVDelimiter(submission.CSVFiles.Delimiter) {
+			return errors.Wrap(ErrorInvalidCSVDelimiter(submission.CSVFiles.Delimiter), schema.CSVFilesKey, schema.DelimiterKey)
+		}
+	}
+
 	if submission.Workers <= 0 {
 		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Workers, 1), schema.WorkersKey)
 	}
 
+	if submission.MaxRetries != nil {
+		if *submission.MaxRetries < 0 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.MaxRetries, 0), schema.MaxRetriesKey)
+		}
+		// sqs limits the maximum receive count of a redrive policy to 1000
+		if *submission.MaxRetries > _maxRetriesLimit {
+			return errors.Wrap(cr.ErrorMustBeLessThanOrEqualTo(*submission.MaxRetries, _maxRetriesLimit), schema.MaxRetriesKey)
+		}
+	}
+
+	if submission.Timeout == nil {
+		submission.Timeout = pointer.Int(_defaultVisibilityTimeout)
+	}
+	// workers extend the visibility timeout at half of the timeout, so a lower timeout would require frequent requests to sqs
+	if *submission.Timeout < _minVisibilityTimeout {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.Timeout, _minVisibilityTimeout), schema.TimeoutKey)
+	}
+	// sqs limits the visibility timeout to 12 hours
+	if *submission.Timeout > _maxVisibilityTimeout {
+		return errors.Wrap(cr.ErrorMustBeLessThanOrEqualTo(*submission.Timeout, _maxVisibilityTimeout), schema.TimeoutKey)
+	}
+
+	if len(submission.Labels) > _maxJobLabels {
+		return errors.Wrap(cr.ErrorTooManyElements(_maxJobLabels), schema.LabelsKey)
+	}
+	for key, value := range submission.Labels {
+		if !regex.IsAlphaNumericDashDotUnderscore(key) {
+			return errors.Wrap(cr.ErrorAlphaNumericDashDotUnderscore(key), schema.LabelsKey)
+		}
+		if len(key) > _maxJobLabelKeyLength {
+			return errors.Wrap(cr.ErrorTooLong(key, _maxJobLabelKeyLength), schema.LabelsKey)
+		}
+		if len(value) > _maxJobLabelValueLength {
+			return errors.Wrap(cr.ErrorTooLong(value, _maxJobLabelValueLength), schema.LabelsKey, key)
+		}
+	}
+
+	if submission.IdempotencyKey != nil {
+		if err := validateIdempotencyKey(*submission.IdempotencyKey); err != nil {
+			return errors.Wrap(err, schema.IdempotencyKeyKey)
+		}
+	}
+
+	if submission.Output != nil {
+		if !awslib.IsValidS3Path(submission.Output.S3Path) {
+			return errors.Wrap(awslib.ErrorInvalidS3Path(submission.Output.S3Path), schema.OutputKey, schema.S3PathKey)
+		}
+
+		if submission.Output.Format == "" {
+			submission.Output.Format = spec.JSONLinesJobOutputFormat
+		}
+		if !slices.HasString(spec.JobOutputFormats, submission.Output.Format) {
+			return errors.Wrap(cr.ErrorInvalidStr(submission.Output.Format, spec.JobOutputFormats[0], spec.JobOutputFormats[1:]...), schema.OutputKey, schema.FormatKey)
+		}
+	}
+
 	return nil
 }
 
@@ -89,24 +172,50 @@ func validateJobSubmission(submission *schema.JobSubmission) error {
 		return errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortex.dev/v/%s/deployments/batch-api/endpoints", consts.CortexVersionMinor))
 	}
 
+	if len(submission.DependsOn) > 0 {
+		err := validateJobDependencies(submission.DependsOn)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the files may be written by the upstream jobs, in which case they are listed once the job starts
+	requireFiles := len(submission.DependsOn) == 0
+
 	if submission.FilePathLister != nil {
-		err := validateS3Lister(&submission.FilePathLister.S3Lister)
+		err := validateS3Lister(&submission.FilePathLister.S3Lister, requireFiles)
 		if err != nil {
 			return errors.Wrap(err, schema.FilePathListerKey)
 		}
 	}
 
 	if submission.DelimitedFiles != nil {
-		err := validateS3Lister(&submission.DelimitedFiles.S3Lister)
+		err := validateS3Lister(&submission.DelimitedFiles.S3Lister, requireFiles)
 		if err != nil {
 			return errors.Wrap(err, schema.DelimitedFilesKey)
 		}
 	}
 
+	if submission.CSVFiles != nil {
+		err := validateS3Lister(&submission.CSVFiles.S3Lister, requireFiles)
+		if err != nil {
+			return errors.Wrap(err, schema.CSVFilesKey)
+		}
+	}
+
 	return nil
 }
 
-func validateS3Lister(s3Lister *schema.S3Lister) error {
+// the delimiter must be a single character which can't be confused with quotes or line endings (the same rules as encoding/csv)
+func isValidCSVDelimiter(delimiter string) bool {
+	if utf8.RuneCountInString(delimiter) != 1 {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(delimiter)
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && r != utf8.RuneError
+}
+
+func validateS3Lister(s3Lister *schema.S3Lister, requireFiles bool) error {
 	if len(s3Lister.S3Paths) == 0 {
 		return errors.Wrap(cr.ErrorTooFewElements(1), schema.S3PathsKey)
 	}
@@ -125,6 +234,15 @@ func validateS3Lister(s3Lister *schema.S3Lister) error {
 		}
 	}
 
+	if !requireFiles {
+		for _, s3Path := range s3Lister.S3Paths {
+			if !awslib.IsValidS3Path(s3Path) {
+				return awslib.ErrorInvalidS3Path(s3Path)
+			}
+		}
+		return nil
+	}
+
 	filesFound := 0
 	for _, s3Path := range s3Lister.S3Paths {
 		if !awslib.IsValidS3Path(s3Path) {
@@ -28,7 +28,7 @@ import (
 const _stalledPodTimeout = 10 * time.Minute
 
 func getWorkerCountsForJob(k8sJob kbatch.Job, pods []kcore.Pod) status.WorkerCounts {
-	if k8sJob.Status.Failed > 0 {
+	if hasWorkerFailures(&k8sJob) {
 		return status.WorkerCounts{
 			Failed: *k8sJob.Spec.Parallelism, // When one worker fails, the rest of the pods get deleted so you won't be able to get their statuses
 		}
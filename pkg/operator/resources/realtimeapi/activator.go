@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	kapps "k8s.io/api/apps/v1"
+)
+
+const (
+	_activatorServiceName    = "operator"
+	_activationTimeout       = 5 * time.Minute
+	_activationPollingPeriod = 1 * time.Second
+
+	// changes to an api's virtual service take a few seconds to propagate, so requests may still be routed to the activator shortly after the api has been activated
+	_activationGracePeriod = 1 * time.Minute
+)
+
+var _activationMutex = sync.Mutex{}
+
+// the time at which each api was last routed away from the activator (guarded by _activationMutex)
+var _activationTimestamps = map[string]time.Time{}
+
+// IsAwaitingActivation returns whether the API's requests are routed to the activator (or were until recently)
+func IsAwaitingActivation(apiName string) (bool, error) {
+	_activationMutex.Lock()
+	defer _activationMutex.Unlock()
+
+	if activationTime, ok := _activationTimestamps[apiName]; ok && time.Since(activationTime) < _activationGracePeriod {
+		return true, nil
+	}
+
+	virtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
+	if err != nil {
+		return false, err
+	}
+	if virtualService == nil {
+		return false, nil
+	}
+
+	return isRoutedToActivator(virtualService), nil
+}
+
+// ActivateAPI scales an API which has been scaled to zero back up, and blocks until one of its replicas is ready to receive traffic
+func ActivateAPI(apiName string) error {
+	if err := requestActivation(apiName); err != nil {
+		return err
+	}
+
+	if err := waitForReadyReplica(apiName, config.K8s.GetDeployment, _activationTimeout, _activationPollingPeriod); err != nil {
+		return err
+	}
+
+	_activationMutex.Lock()
+	defer _activationMutex.Unlock()
+
+	if err := routeVirtualService(apiName, false); err != nil {
+		return err
+	}
+
+	if _, ok := _activationTimestamps[apiName]; !ok {
+		_activationTimestamps[apiName] = time.Now()
+	}
+
+	return nil
+}
+
+func requestActivation(apiName string) error {
+	_activationMutex.Lock()
+	defer _activationMutex.Unlock()
+
+	deployment, err := config.K8s.GetDeployment(operator.K8sName(apiName))
+	if err != nil {
+		return err
+	}
+	if deployment == nil {
+		return errors.ErrorUnexpected("unable to find deployment", apiName)
+	}
+
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > 0 {
+		return nil
+	}
+
+	log.Printf("%s activator: scaling from 0 -> %d", apiName, autoscaler.ActivationReplicaRequest)
+
+	replicas := autoscaler.ActivationReplicaRequest
+	deployment.Spec.Replicas = &replicas
+	if _, err := config.K8s.UpdateDeployment(deployment); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func waitForReadyReplica(apiName string, getDeployment func(string) (*kapps.Deployment, error), timeout time.Duration, pollingPeriod time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		deployment, err := getDeployment(operator.K8sName(apiName))
+		if err != nil {
+			return err
+		}
+		if deployment == nil {
+			return ErrorAPINotDeployed(apiName)
+		}
+
+		if deployment.Status.ReadyReplicas > 0 {
+			return nil
+		}
+
+		time.Sleep(pollingPeriod)
+	}
+
+	return ErrorActivationTimeout(apiName, timeout)
+}
+
+// routeVirtualService points the API's virtual service at either the API's replicas or at the activator (_activationMutex must be held)
+func routeVirtualService(apiName string, scaledToZero bool) error {
+	prevVirtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
+	if err != nil {
+		return err
+	}
+	if prevVirtualService == nil {
+		return errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
+
+	if isRoutedToActivator(prevVirtualService) == scaledToZero {
+		return nil
+	}
+
+	api, err := operator.DownloadAPISpec(apiName, prevVirtualService.Labels["apiID"])
+	if err != nil {
+		return err
+	}
+
+	return applyK8sVirtualService(api, prevVirtualService, scaledToZero)
+}
+
+func isRoutedToActivator(virtualService *istioclientnetworking.VirtualService) bool {
+	for _, httpRoute := range virtualService.Spec.Http {
+		for _, route := range httpRoute.Route {
+			if route.Destination != nil && route.Destination.Host == _activatorServiceName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func scaleToZero(deployment *kapps.Deployment) error {
+	apiName := deployment.Labels["apiName"]
+
+	_activationMutex.Lock()
+	defer _activationMutex.Unlock()
+
+	// route traffic to the activator before removing the replicas so that no requests are dropped
+	if err := routeVirtualService(apiName, true); err != nil {
+		return errors.Wrap(err, "route to activator")
+	}
+
+	delete(_activationTimestamps, apiName)
+
+	var zero int32
+	deployment.Spec.Replicas = &zero
+
+	if _, err := config.K8s.UpdateDeployment(deployment); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	kapps "k8s.io/api/apps/v1"
+)
+
+func testDeployment(readyReplicas int32) *kapps.Deployment {
+	deployment := &kapps.Deployment{}
+	deployment.Status.ReadyReplicas = readyReplicas
+	return deployment
+}
+
+func TestWaitForReadyReplica(t *testing.T) {
+	var calls int
+	getDeployment := func(name string) (*kapps.Deployment, error) {
+		require.Equal(t, operator.K8sName("test"), name)
+		calls++
+		if calls < 3 {
+			return testDeployment(0), nil
+		}
+		return testDeployment(1), nil
+	}
+
+	err := waitForReadyReplica("test", getDeployment, time.Second, time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+}
+
+func TestWaitForReadyReplicaTimeout(t *testing.T) {
+	getDeployment := func(name string) (*kapps.Deployment, error) {
+		return testDeployment(0), nil
+	}
+
+	err := waitForReadyReplica("test", getDeployment, 10*time.Millisecond, time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, ErrActivationTimeout, errors.GetKind(err))
+}
+
+func TestWaitForReadyReplicaNotDeployed(t *testing.T) {
+	getDeployment := func(name string) (*kapps.Deployment, error) {
+		return nil, nil
+	}
+
+	err := waitForReadyReplica("test", getDeployment, time.Second, time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, ErrAPINotDeployed, errors.GetKind(err))
+}
+
+func TestVirtualServiceSpecActivatorRouting(t *testing.T) {
+	api := &spec.API{
+		API: &userconfig.API{
+			Resource: userconfig.Resource{
+				Name: "test",
+				Kind: userconfig.RealtimeAPIKind,
+			},
+			Networking: &userconfig.Networking{
+				Endpoint: pointer.String("test"),
+			},
+		},
+	}
+
+	virtualService := virtualServiceSpec(api, false)
+	require.False(t, isRoutedToActivator(virtualService))
+	require.Equal(t, operator.K8sName("test"), virtualService.Spec.Http[0].Route[0].Destination.Host)
+	require.Equal(t, "/predict", virtualService.Spec.Http[0].Rewrite.Uri)
+
+	virtualService = virtualServiceSpec(api, true)
+	require.True(t, isRoutedToActivator(virtualService))
+	require.Equal(t, uint32(operator.ActivatorPortInt32), virtualService.Spec.Http[0].Route[0].Destination.Port.Number)
+	require.Equal(t, "/activate/test", virtualService.Spec.Http[0].Rewrite.Uri)
+}
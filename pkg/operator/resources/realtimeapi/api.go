@@ -19,6 +19,7 @@ package realtimeapi
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -279,13 +280,19 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 		return nil, err
 	}
 
+	var activeSchedule *userconfig.AutoscalingSchedule
+	if api.Autoscaling != nil {
+		activeSchedule = api.Autoscaling.ActiveSchedule(time.Now())
+	}
+
 	return &schema.GetAPIResponse{
 		RealtimeAPI: &schema.RealtimeAPI{
-			Spec:         *api,
-			Status:       *status,
-			Metrics:      *metrics,
-			Endpoint:     apiEndpoint,
-			DashboardURL: DashboardURL(),
+			Spec:                      *api,
+			Status:                    *status,
+			Metrics:                   *metrics,
+			Endpoint:                  apiEndpoint,
+			DashboardURL:              DashboardURL(),
+			ActiveAutoscalingSchedule: activeSchedule,
 		},
 	}, nil
 }
@@ -325,7 +332,8 @@ func applyK8sResources(api *spec.API, prevDeployment *kapps.Deployment, prevServ
 			return applyK8sService(api, prevService)
 		},
 		func() error {
-			return applyK8sVirtualService(api, prevVirtualService)
+			scaledToZero := getRequestedReplicasFromDeployment(api, prevDeployment) == 0
+			return applyK8sVirtualService(api, prevVirtualService, scaledToZero)
 		},
 	)
 }
@@ -366,7 +374,7 @@ func UpdateAutoscalerCron(deployment *kapps.Deployment) error {
 		prevAutoscalerCron.Cancel()
 	}
 
-	autoscaler, err := autoscaleFn(deployment)
+	autoscaler, err := autoscaleFn(deployment, getInFlightRequestSource())
 	if err != nil {
 		return err
 	}
@@ -388,8 +396,8 @@ func applyK8sService(api *spec.API, prevService *kcore.Service) error {
 	return err
 }
 
-func applyK8sVirtualService(api *spec.API, prevVirtualService *istioclientnetworking.VirtualService) error {
-	newVirtualService := virtualServiceSpec(api)
+func applyK8sVirtualService(api *spec.API, prevVirtualService *istioclientnetworking.VirtualService, scaledToZero bool) error {
+	newVirtualService := virtualServiceSpec(api, scaledToZero)
 
 	if prevVirtualService == nil {
 		_, err := config.K8s.CreateVirtualService(newVirtualService)
@@ -407,6 +415,7 @@ func deleteK8sResources(apiName string) error {
 				autoscalerCron.Cancel()
 				delete(_autoscalerCrons, apiName)
 			}
+			deleteAutoscalerDecisions(apiName)
 
 			_, err := config.K8s.DeleteDeployment(operator.K8sName(apiName))
 			return err
@@ -419,6 +428,9 @@ func deleteK8sResources(apiName string) error {
 			_, err := config.K8s.DeleteVirtualService(operator.K8sName(apiName))
 			return err
 		},
+		func() error {
+			return deleteAutoscalerCheckpoint(apiName)
+		},
 	)
 }
 
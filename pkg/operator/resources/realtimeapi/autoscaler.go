@@ -17,249 +17,173 @@ limitations under the License.
 package realtimeapi
 
 import (
+	"fmt"
 	"log"
-	"math"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/cloudwatch"
-	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
-	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
-	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	kapps "k8s.io/api/apps/v1"
 )
 
-type recommendations map[time.Time]int32
-
-func (recs recommendations) add(rec int32) {
-	recs[time.Now()] = rec
-}
-
-func (recs recommendations) deleteOlderThan(period time.Duration) {
-	for t := range recs {
-		if time.Since(t) > period {
-			delete(recs, t)
-		}
-	}
-}
-
-// Returns nil if no recommendations in the period
-func (recs recommendations) maxSince(period time.Duration) *int32 {
-	max := int32(math.MinInt32)
-	foundRecommendation := false
-
-	for t, rec := range recs {
-		if time.Since(t) <= period && rec > max {
-			max = rec
-			foundRecommendation = true
-		}
-	}
-
-	if !foundRecommendation {
-		return nil
-	}
-
-	return &max
-}
-
-// Returns nil if no recommendations in the period
-func (recs recommendations) minSince(period time.Duration) *int32 {
-	min := int32(math.MaxInt32)
-	foundRecommendation := false
-
-	for t, rec := range recs {
-		if time.Since(t) <= period && rec < min {
-			min = rec
-			foundRecommendation = true
-		}
-	}
-
-	if !foundRecommendation {
-		return nil
-	}
-
-	return &min
-}
-
-func autoscaleFn(initialDeployment *kapps.Deployment) (func() error, error) {
+func autoscaleFn(initialDeployment *kapps.Deployment, inFlightSource inFlightRequestSource) (func() error, error) {
 	autoscalingSpec, err := userconfig.AutoscalingFromAnnotations(initialDeployment)
 	if err != nil {
 		return nil, err
 	}
 
 	apiName := initialDeployment.Labels["apiName"]
+	apiID := initialDeployment.Labels["apiID"]
 	currentReplicas := *initialDeployment.Spec.Replicas
 
-	log.Printf("%s autoscaler init", apiName)
-
-	var startTime time.Time
-	recs := make(recommendations)
+	var state *autoscaler.State
+	var lastCheckpointTime time.Time
+	var seasonalProfile *autoscaler.SeasonalProfile
+	var lastSeasonalProfileSaveTime time.Time
 
-	return func() error {
-		if startTime.IsZero() {
-			startTime = time.Now()
+	// resume from the previous autoscaler's state (e.g. before the operator restarted) so that the stabilization periods aren't reset
+	checkpoint, err := getAutoscalerCheckpoint(apiName, apiID, autoscalingSpec)
+	if err != nil {
+		log.Printf("%s autoscaler: unable to restore checkpoint: %s", apiName, errors.Message(err))
+	} else if checkpoint != nil {
+		state = &autoscaler.State{
+			StartTime:       checkpoint.StartTime,
+			LastActiveTime:  checkpoint.LastActiveTime,
+			CurrentReplicas: currentReplicas,
+			Recommendations: checkpoint.Recommendations,
 		}
+	}
 
-		avgInFlight, err := getInflightRequests(apiName, autoscalingSpec.Window)
+	if autoscalingSpec.PredictiveScaling {
+		seasonalProfile, err = getSeasonalProfile(apiName)
 		if err != nil {
-			return err
-		}
-		if avgInFlight == nil {
-			log.Printf("%s autoscaler tick: metrics not available yet", apiName)
-			return nil
-		}
-
-		rawRecommendation := *avgInFlight / *autoscalingSpec.TargetReplicaConcurrency
-		recommendation := int32(math.Ceil(rawRecommendation))
-
-		if rawRecommendation < float64(currentReplicas) && rawRecommendation > float64(currentReplicas)*(1-autoscalingSpec.DownscaleTolerance) {
-			recommendation = currentReplicas
-		}
-
-		if rawRecommendation > float64(currentReplicas) && rawRecommendation < float64(currentReplicas)*(1+autoscalingSpec.UpscaleTolerance) {
-			recommendation = currentReplicas
+			log.Printf("%s autoscaler: unable to load seasonal profile (starting with an empty profile): %s", apiName, errors.Message(err))
+			seasonalProfile = autoscaler.NewSeasonalProfile()
 		}
+	}
 
-		// always allow subtraction of 1
-		downscaleFactorFloor := libmath.MinInt32(currentReplicas-1, int32(math.Ceil(float64(currentReplicas)*autoscalingSpec.MaxDownscaleFactor)))
-		if recommendation < downscaleFactorFloor {
-			recommendation = downscaleFactorFloor
-		}
+	log.Printf("%s autoscaler init (restored from checkpoint: %t)", apiName, checkpoint != nil)
 
-		// always allow addition of 1
-		upscaleFactorCeil := libmath.MaxInt32(currentReplicas+1, int32(math.Ceil(float64(currentReplicas)*autoscalingSpec.MaxUpscaleFactor)))
-		if recommendation > upscaleFactorCeil {
-			recommendation = upscaleFactorCeil
+	return func() error {
+		if state == nil {
+			state = autoscaler.NewState(time.Now(), currentReplicas)
 		}
+		state.SeasonalProfile = seasonalProfile
 
-		if recommendation < 1 {
-			recommendation = 1
-		}
+		defer func() {
+			if time.Since(lastCheckpointTime) < _autoscalerCheckpointPeriod {
+				return
+			}
+			err := saveAutoscalerCheckpoint(apiName, &autoscalerCheckpoint{
+				APIID:           apiID,
+				StartTime:       state.StartTime,
+				LastActiveTime:  state.LastActiveTime,
+				Recommendations: state.Recommendations,
+			})
+			if err != nil {
+				log.Printf("%s autoscaler: unable to save checkpoint: %s", apiName, errors.Message(err))
+				return
+			}
+			lastCheckpointTime = time.Now()
+		}()
+
+		if seasonalProfile != nil {
+			defer func() {
+				if time.Since(lastSeasonalProfileSaveTime) < _seasonalProfileSavePeriod {
+					return
+				}
+				if err := saveSeasonalProfile(apiName, seasonalProfile); err != nil {
+					log.Printf("%s autoscaler: unable to save seasonal profile: %s", apiName, errors.Message(err))
+					return
+				}
+				lastSeasonalProfileSaveTime = time.Now()
+			}()
+		}
+
+		if state.CurrentReplicas == 0 {
+			// the activator scales the API back up when it receives a request
+			deployment, err := config.K8s.GetDeployment(initialDeployment.Name)
+			if err != nil {
+				return err
+			}
+			if deployment == nil || deployment.Spec.Replicas == nil || *deployment.Spec.Replicas == 0 {
+				decision := autoscaler.Decide(autoscalingSpec, state, time.Now(), nil, nil)
+				if deployment != nil && decision.MinReplicas > 0 {
+					// e.g. a scheduled scaling window or predictive scaling requires the API to be running
+					log.Printf("%s autoscaler tick: activating (active_schedule=%s, predicted_in_flight=%s, predictive_floor=%s, min_replicas=%d)", apiName, activeScheduleStr(decision.ActiveSchedule), s.ObjFlatNoQuotes(decision.PredictedInFlight), s.ObjFlatNoQuotes(decision.PredictiveFloor), decision.MinReplicas)
+					go func() {
+						if err := ActivateAPI(apiName); err != nil {
+							log.Printf("%s autoscaler: unable to activate: %s", apiName, errors.Message(err))
+						}
+					}()
+				}
+				recordAutoscalerDecision(apiName, decision)
+				return nil
+			}
 
-		if recommendation < autoscalingSpec.MinReplicas {
-			recommendation = autoscalingSpec.MinReplicas
+			log.Printf("%s autoscaler tick: activated (0 -> %d)", apiName, *deployment.Spec.Replicas)
+			state.CurrentReplicas = *deployment.Spec.Replicas
+			state.LastActiveTime = time.Now()
 		}
 
-		if recommendation > autoscalingSpec.MaxReplicas {
-			recommendation = autoscalingSpec.MaxReplicas
+		avgInFlight, err := inFlightSource.avgInFlight(apiName, autoscalingSpec.Window)
+		if err != nil {
+			return err
 		}
 
-		// Rule of thumb: any modifications that don't consider historical recommendations should be performed before
-		// recording the recommendation, any modifications that use historical recommendations should be performed after
-		recs.add(recommendation)
-
-		// This is just for garbage collection
-		recs.deleteOlderThan(libtime.MaxDuration(autoscalingSpec.DownscaleStabilizationPeriod, autoscalingSpec.UpscaleStabilizationPeriod))
-
-		request := recommendation
-
-		downscaleStabilizationFloor := recs.maxSince(autoscalingSpec.DownscaleStabilizationPeriod)
-		if time.Since(startTime) < autoscalingSpec.DownscaleStabilizationPeriod {
-			if request < currentReplicas {
-				request = currentReplicas
+		var policyRecs []schema.AutoscalerPolicyRecommendation
+		if avgInFlight != nil {
+			policyRecs, err = getPolicyRecommendations(apiName, autoscalingSpec, state.CurrentReplicas)
+			if err != nil {
+				return err
 			}
-		} else if downscaleStabilizationFloor != nil && request < *downscaleStabilizationFloor {
-			request = *downscaleStabilizationFloor
 		}
 
-		upscaleStabilizationCeil := recs.minSince(autoscalingSpec.UpscaleStabilizationPeriod)
-		if time.Since(startTime) < autoscalingSpec.UpscaleStabilizationPeriod {
-			if request > currentReplicas {
-				request = currentReplicas
-			}
-		} else if upscaleStabilizationCeil != nil && request > *upscaleStabilizationCeil {
-			request = *upscaleStabilizationCeil
+		decision := autoscaler.Decide(autoscalingSpec, state, time.Now(), avgInFlight, policyRecs)
+		recordAutoscalerDecision(apiName, decision)
+
+		if avgInFlight == nil {
+			log.Printf("%s autoscaler tick: metrics not available yet", apiName)
+			return nil
 		}
 
-		log.Printf("%s autoscaler tick: avg_in_flight=%s, target_replica_concurrency=%s, raw_recommendation=%s, current_replicas=%d, downscale_tolerance=%s, upscale_tolerance=%s, max_downscale_factor=%s, downscale_factor_floor=%d, max_upscale_factor=%s, upscale_factor_ceil=%d, min_replicas=%d, max_replicas=%d, recommendation=%d, downscale_stabilization_period=%s, downscale_stabilization_floor=%s, upscale_stabilization_period=%s, upscale_stabilization_ceil=%s, request=%d", apiName, s.Round(*avgInFlight, 2, 0), s.Float64(*autoscalingSpec.TargetReplicaConcurrency), s.Round(rawRecommendation, 2, 0), currentReplicas, s.Float64(autoscalingSpec.DownscaleTolerance), s.Float64(autoscalingSpec.UpscaleTolerance), s.Float64(autoscalingSpec.MaxDownscaleFactor), downscaleFactorFloor, s.Float64(autoscalingSpec.MaxUpscaleFactor), upscaleFactorCeil, autoscalingSpec.MinReplicas, autoscalingSpec.MaxReplicas, recommendation, autoscalingSpec.DownscaleStabilizationPeriod, s.ObjFlatNoQuotes(downscaleStabilizationFloor), autoscalingSpec.UpscaleStabilizationPeriod, s.ObjFlatNoQuotes(upscaleStabilizationCeil), request)
+		log.Printf("%s autoscaler tick: avg_in_flight=%s, target_replica_concurrency=%s, policies=%s, raw_recommendation=%s, current_replicas=%d, downscale_tolerance=%s, upscale_tolerance=%s, max_downscale_factor=%s, downscale_factor_floor=%d, max_upscale_factor=%s, upscale_factor_ceil=%d, active_schedule=%s, predicted_in_flight=%s, predictive_floor=%s, min_replicas=%d, max_replicas=%d, recommendation=%d, downscale_stabilization_period=%s, downscale_stabilization_floor=%s, upscale_stabilization_period=%s, upscale_stabilization_ceil=%s, scale_to_zero_period=%s, idle_time=%s, request=%d", apiName, s.Round(*avgInFlight, 2, 0), s.Float64(*autoscalingSpec.TargetReplicaConcurrency), policyRecommendationsStr(policyRecs), s.Round(decision.RawRecommendation, 2, 0), decision.CurrentReplicas, s.Float64(autoscalingSpec.DownscaleTolerance), s.Float64(autoscalingSpec.UpscaleTolerance), s.Float64(autoscalingSpec.MaxDownscaleFactor), decision.DownscaleFactorFloor, s.Float64(autoscalingSpec.MaxUpscaleFactor), decision.UpscaleFactorCeil, activeScheduleStr(decision.ActiveSchedule), s.ObjFlatNoQuotes(decision.PredictedInFlight), s.ObjFlatNoQuotes(decision.PredictiveFloor), decision.MinReplicas, decision.MaxReplicas, decision.Recommendation, autoscalingSpec.DownscaleStabilizationPeriod, s.ObjFlatNoQuotes(decision.DownscaleStabilizationFloor), autoscalingSpec.UpscaleStabilizationPeriod, s.ObjFlatNoQuotes(decision.UpscaleStabilizationCeil), autoscalingSpec.ScaleToZeroPeriod, decision.IdleTime, decision.Request)
 
-		if currentReplicas != request {
-			log.Printf("%s autoscaling event: %d -> %d", apiName, currentReplicas, request)
+		request := decision.Request
+		if state.CurrentReplicas != request {
+			log.Printf("%s autoscaling event: %d -> %d", apiName, state.CurrentReplicas, request)
 
 			deployment, err := config.K8s.GetDeployment(initialDeployment.Name)
 			if err != nil {
 				return err
 			}
 
-			deployment.Spec.Replicas = &request
+			if request == 0 {
+				if err := scaleToZero(deployment); err != nil {
+					return err
+				}
+			} else {
+				deployment.Spec.Replicas = &request
 
-			if _, err := config.K8s.UpdateDeployment(deployment); err != nil {
-				return err
+				if _, err := config.K8s.UpdateDeployment(deployment); err != nil {
+					return err
+				}
 			}
 
-			currentReplicas = request
+			state.CurrentReplicas = request
 		}
 
 		return nil
 	}, nil
 }
 
-func getInflightRequests(apiName string, window time.Duration) (*float64, error) {
-	endTime := time.Now().Truncate(time.Second)
-	startTime := endTime.Add(-2 * window)
-	metricsDataQuery := cloudwatch.GetMetricDataInput{
-		EndTime:   &endTime,
-		StartTime: &startTime,
-		MetricDataQueries: []*cloudwatch.MetricDataQuery{
-			{
-				Id:    aws.String("inflight"),
-				Label: aws.String("InFlight"),
-				MetricStat: &cloudwatch.MetricStat{
-					Metric: &cloudwatch.Metric{
-						Namespace:  aws.String(config.Cluster.ClusterName),
-						MetricName: aws.String("in-flight"),
-						Dimensions: []*cloudwatch.Dimension{
-							{
-								Name:  aws.String("apiName"),
-								Value: aws.String(apiName),
-							},
-						},
-					},
-					Stat:   aws.String("Sum"),
-					Period: aws.Int64(10),
-				},
-			},
-		},
-	}
-
-	output, err := config.AWS.CloudWatch().GetMetricData(&metricsDataQuery)
-	if err != nil {
-		return nil, err
-	}
-	if len(output.MetricDataResults) == 0 {
-		return nil, nil
-	}
-
-	timestampCounter := -1
-	for i, timeStamp := range output.MetricDataResults[0].Timestamps {
-		if endTime.Sub(*timeStamp) < 20*time.Second {
-			timestampCounter = i
-		} else {
-			break
-		}
-	}
-
-	if timestampCounter == -1 {
-		return nil, nil // no metrics were available in the last 2 tick intervals
-	}
-
-	steps := int(window.Nanoseconds() / spec.AutoscalingTickInterval.Nanoseconds())
-
-	endTimeStampCounter := libmath.MinInt(timestampCounter+steps, len(output.MetricDataResults[0].Timestamps))
-
-	values := output.MetricDataResults[0].Values[timestampCounter:endTimeStampCounter]
-	if len(values) == 0 {
-		return nil, nil
+func activeScheduleStr(schedule *string) string {
+	if schedule == nil {
+		return "none"
 	}
-
-	avg := 0.0
-	for _, val := range values {
-		avg += *val
-	}
-	avg = avg / float64(len(values))
-
-	return &avg, nil
+	return fmt.Sprintf("\"%s\"", *schedule)
 }
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const (
+	_autoscalerCheckpointPeriod = 30 * time.Second
+	_autoscalerCheckpointKey    = "checkpoint"
+)
+
+// autoscalerCheckpoint is the autoscaler's state, which is stored in a config map so that it survives operator restarts
+type autoscalerCheckpoint struct {
+	APIID           string                     `json:"api_id"`
+	SavedAt         time.Time                  `json:"saved_at"`
+	StartTime       time.Time                  `json:"start_time"`
+	LastActiveTime  time.Time                  `json:"last_active_time"`
+	Recommendations autoscaler.Recommendations `json:"recommendations"`
+}
+
+func autoscalerConfigMapName(apiName string) string {
+	return "autoscaler-" + apiName
+}
+
+// Returns nil if there is no checkpoint for this version of the API, or if it is too old to be relevant
+func getAutoscalerCheckpoint(apiName string, apiID string, autoscalingSpec *userconfig.Autoscaling) (*autoscalerCheckpoint, error) {
+	configMapData, err := config.K8s.GetConfigMapData(autoscalerConfigMapName(apiName))
+	if err != nil {
+		return nil, err
+	}
+
+	checkpointStr, ok := configMapData[_autoscalerCheckpointKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var checkpoint autoscalerCheckpoint
+	if err := json.Unmarshal([]byte(checkpointStr), &checkpoint); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), autoscalerConfigMapName(apiName))
+	}
+
+	if checkpoint.APIID != apiID {
+		return nil, nil
+	}
+
+	// the recommendations from before the gap would no longer cover the stabilization periods
+	maxStabilizationPeriod := autoscalingSpec.DownscaleStabilizationPeriod
+	if autoscalingSpec.UpscaleStabilizationPeriod > maxStabilizationPeriod {
+		maxStabilizationPeriod = autoscalingSpec.UpscaleStabilizationPeriod
+	}
+	if time.Since(checkpoint.SavedAt) > maxStabilizationPeriod {
+		return nil, nil
+	}
+
+	if checkpoint.Recommendations == nil {
+		checkpoint.Recommendations = make(autoscaler.Recommendations)
+	}
+
+	return &checkpoint, nil
+}
+
+func saveAutoscalerCheckpoint(apiName string, checkpoint *autoscalerCheckpoint) error {
+	checkpoint.SavedAt = time.Now()
+
+	checkpointBytes, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	configMap := k8s.ConfigMap(&k8s.ConfigMapSpec{
+		Name: autoscalerConfigMapName(apiName),
+		Data: map[string]string{
+			_autoscalerCheckpointKey: string(checkpointBytes),
+		},
+		Labels: map[string]string{
+			"apiName": apiName,
+			"apiKind": userconfig.RealtimeAPIKind.String(),
+		},
+	})
+
+	_, err = config.K8s.ApplyConfigMap(configMap)
+	return err
+}
+
+func deleteAutoscalerCheckpoint(apiName string) error {
+	_, err := config.K8s.DeleteConfigMap(autoscalerConfigMapName(apiName))
+	return err
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+const _autoscalerDecisionLogSize = 360 // one hour of ticks
+
+var (
+	_autoscalerDecisionLogs      = make(map[string]*autoscalerDecisionLog) // apiName -> decision log
+	_autoscalerDecisionLogsMutex = sync.Mutex{}
+)
+
+// autoscalerDecisionLog is a ring buffer of an API's most recent autoscaler decisions
+type autoscalerDecisionLog struct {
+	decisions []schema.AutoscalerDecision
+	next      int
+}
+
+func recordAutoscalerDecision(apiName string, decision schema.AutoscalerDecision) {
+	_autoscalerDecisionLogsMutex.Lock()
+	defer _autoscalerDecisionLogsMutex.Unlock()
+
+	decisionLog, ok := _autoscalerDecisionLogs[apiName]
+	if !ok {
+		decisionLog = &autoscalerDecisionLog{}
+		_autoscalerDecisionLogs[apiName] = decisionLog
+	}
+
+	if len(decisionLog.decisions) < _autoscalerDecisionLogSize {
+		decisionLog.decisions = append(decisionLog.decisions, decision)
+		return
+	}
+
+	decisionLog.decisions[decisionLog.next] = decision
+	decisionLog.next = (decisionLog.next + 1) % _autoscalerDecisionLogSize
+}
+
+// GetAutoscalerDecisions returns the API's most recent autoscaler decisions (at most limit, if limit is positive), oldest first
+func GetAutoscalerDecisions(apiName string, limit int) []schema.AutoscalerDecision {
+	_autoscalerDecisionLogsMutex.Lock()
+	defer _autoscalerDecisionLogsMutex.Unlock()
+
+	decisionLog, ok := _autoscalerDecisionLogs[apiName]
+	if !ok {
+		return []schema.AutoscalerDecision{}
+	}
+
+	numDecisions := len(decisionLog.decisions)
+	decisions := make([]schema.AutoscalerDecision, 0, numDecisions)
+	decisions = append(decisions, decisionLog.decisions[decisionLog.next:]...)
+	decisions = append(decisions, decisionLog.decisions[:decisionLog.next]...)
+
+	if limit > 0 && limit < numDecisions {
+		decisions = decisions[numDecisions-limit:]
+	}
+
+	return decisions
+}
+
+func deleteAutoscalerDecisions(apiName string) {
+	_autoscalerDecisionLogsMutex.Lock()
+	defer _autoscalerDecisionLogsMutex.Unlock()
+
+	delete(_autoscalerDecisionLogs, apiName)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	kapps "k8s.io/api/apps/v1"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeInFlightSource returns its values in order, one per tick
+type fakeInFlightSource struct {
+	values []*float64
+	window time.Duration
+}
+
+func (source *fakeInFlightSource) avgInFlight(apiName string, window time.Duration) (*float64, error) {
+	source.window = window
+	value := source.values[0]
+	source.values = source.values[1:]
+	return value, nil
+}
+
+func TestAutoscaleFn(t *testing.T) {
+	prevK8s := config.K8s
+	defer func() { config.K8s = prevK8s }()
+
+	api := &userconfig.API{
+		Autoscaling: &userconfig.Autoscaling{
+			MinReplicas:              1,
+			MaxReplicas:              10,
+			InitReplicas:             1,
+			TargetReplicaConcurrency: pointer.Float64(1),
+			MaxReplicaConcurrency:    1024,
+			Window:                   time.Minute,
+			MaxDownscaleFactor:       0.5,
+			MaxUpscaleFactor:         10,
+			ScaleToZeroPeriod:        time.Hour,
+		},
+	}
+
+	replicas := int32(1)
+	deployment := &kapps.Deployment{
+		ObjectMeta: kmeta.ObjectMeta{
+			Name:        operator.K8sName("test"),
+			Namespace:   "default",
+			Labels:      map[string]string{"apiName": "test", "apiID": "test-id"},
+			Annotations: api.ToK8sAnnotations(),
+		},
+		Spec: kapps.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	config.K8s = k8s.NewForClientset("default", kfake.NewSimpleClientset(deployment))
+
+	source := &fakeInFlightSource{
+		values: []*float64{nil, pointer.Float64(4), pointer.Float64(2.5), pointer.Float64(0)},
+	}
+
+	autoscale, err := autoscaleFn(deployment, source)
+	require.NoError(t, err)
+
+	for _, expectedReplicas := range []int32{1, 4, 3, 2} {
+		require.NoError(t, autoscale())
+
+		deployment, err := config.K8s.GetDeployment(operator.K8sName("test"))
+		require.NoError(t, err)
+		require.Equal(t, expectedReplicas, *deployment.Spec.Replicas)
+	}
+
+	require.Empty(t, source.values)
+	require.Equal(t, time.Minute, source.window)
+
+	// the autoscaler's state is checkpointed so that it can be restored after the operator restarts
+	configMapData, err := config.K8s.GetConfigMapData(autoscalerConfigMapName("test"))
+	require.NoError(t, err)
+	require.Contains(t, configMapData, _autoscalerCheckpointKey)
+}
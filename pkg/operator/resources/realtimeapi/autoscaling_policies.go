@@ -0,0 +1,221 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+func hasAutoscalingPolicies(autoscalingSpec *userconfig.Autoscaling) bool {
+	return autoscalingSpec.TargetP95Latency != nil || autoscalingSpec.TargetRequestsPerSecond != nil || autoscalingSpec.TargetGPUUtilization != nil
+}
+
+// Returns a recommendation for each of the API's optional autoscaling policies for which metrics are available in the window
+func getPolicyRecommendations(apiName string, autoscalingSpec *userconfig.Autoscaling, currentReplicas int32) ([]schema.AutoscalerPolicyRecommendation, error) {
+	if !hasAutoscalingPolicies(autoscalingSpec) {
+		return nil, nil
+	}
+
+	results, err := queryPolicyMetrics(apiName, autoscalingSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var recs []schema.AutoscalerPolicyRecommendation
+
+	if autoscalingSpec.TargetP95Latency != nil {
+		// latency is reported in milliseconds
+		p95Latency, ok := weightedAverage(results["p95_latency"], results["request_count"])
+		if ok {
+			target := float64(*autoscalingSpec.TargetP95Latency) / float64(time.Millisecond)
+			recs = append(recs, schema.AutoscalerPolicyRecommendation{
+				Policy:            userconfig.TargetP95LatencyKey,
+				Metric:            p95Latency,
+				Target:            target,
+				RawRecommendation: float64(currentReplicas) * p95Latency / target,
+			})
+		}
+	}
+
+	if autoscalingSpec.TargetRequestsPerSecond != nil {
+		// no samples means that no requests were received
+		requestCount, _ := sumValues(results["request_count"])
+		requestsPerSecond := requestCount / autoscalingSpec.Window.Seconds()
+		recs = append(recs, schema.AutoscalerPolicyRecommendation{
+			Policy:            userconfig.TargetRequestsPerSecondKey,
+			Metric:            requestsPerSecond,
+			Target:            *autoscalingSpec.TargetRequestsPerSecond,
+			RawRecommendation: requestsPerSecond / *autoscalingSpec.TargetRequestsPerSecond,
+		})
+	}
+
+	if autoscalingSpec.TargetGPUUtilization != nil {
+		gpuUtilizationSum, okSum := sumValues(results["gpu_utilization_sum"])
+		gpuUtilizationCount, okCount := sumValues(results["gpu_utilization_count"])
+		if okSum && okCount && gpuUtilizationCount > 0 {
+			gpuUtilization := gpuUtilizationSum / gpuUtilizationCount
+			recs = append(recs, schema.AutoscalerPolicyRecommendation{
+				Policy:            userconfig.TargetGPUUtilizationKey,
+				Metric:            gpuUtilization,
+				Target:            *autoscalingSpec.TargetGPUUtilization,
+				RawRecommendation: float64(currentReplicas) * gpuUtilization / *autoscalingSpec.TargetGPUUtilization,
+			})
+		}
+	}
+
+	return recs, nil
+}
+
+func policyRecommendationsStr(recs []schema.AutoscalerPolicyRecommendation) string {
+	if len(recs) == 0 {
+		return "none"
+	}
+
+	strs := make([]string, len(recs))
+	for i, rec := range recs {
+		strs[i] = fmt.Sprintf("%s(metric=%s, target=%s, raw_recommendation=%s)", rec.Policy, s.Round(rec.Metric, 2, 0), s.Float64(rec.Target), s.Round(rec.RawRecommendation, 2, 0))
+	}
+	return strings.Join(strs, " ")
+}
+
+// Returns the values of each query in the window, keyed by query ID
+func queryPolicyMetrics(apiName string, autoscalingSpec *userconfig.Autoscaling) (map[string][]*float64, error) {
+	period := aws.Int64(int64(spec.AutoscalingTickInterval.Seconds()))
+
+	dimensions := []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("APIName"),
+			Value: aws.String(apiName),
+		},
+		{
+			Name:  aws.String("metric_type"),
+			Value: aws.String("histogram"),
+		},
+	}
+
+	latencyMetric := &cloudwatch.Metric{
+		Namespace:  aws.String(config.Cluster.ClusterName),
+		MetricName: aws.String("Latency"),
+		Dimensions: dimensions,
+	}
+
+	gpuUtilizationMetric := &cloudwatch.Metric{
+		Namespace:  aws.String(config.Cluster.ClusterName),
+		MetricName: aws.String("GPUUtilization"),
+		Dimensions: dimensions,
+	}
+
+	var queries []*cloudwatch.MetricDataQuery
+
+	if autoscalingSpec.TargetP95Latency != nil || autoscalingSpec.TargetRequestsPerSecond != nil {
+		queries = append(queries, &cloudwatch.MetricDataQuery{
+			Id:         aws.String("request_count"),
+			MetricStat: &cloudwatch.MetricStat{Metric: latencyMetric, Stat: aws.String("SampleCount"), Period: period},
+		})
+	}
+
+	if autoscalingSpec.TargetP95Latency != nil {
+		queries = append(queries, &cloudwatch.MetricDataQuery{
+			Id:         aws.String("p95_latency"),
+			MetricStat: &cloudwatch.MetricStat{Metric: latencyMetric, Stat: aws.String("p95"), Period: period},
+		})
+	}
+
+	if autoscalingSpec.TargetGPUUtilization != nil {
+		queries = append(queries,
+			&cloudwatch.MetricDataQuery{
+				Id:         aws.String("gpu_utilization_sum"),
+				MetricStat: &cloudwatch.MetricStat{Metric: gpuUtilizationMetric, Stat: aws.String("Sum"), Period: period},
+			},
+			&cloudwatch.MetricDataQuery{
+				Id:         aws.String("gpu_utilization_count"),
+				MetricStat: &cloudwatch.MetricStat{Metric: gpuUtilizationMetric, Stat: aws.String("SampleCount"), Period: period},
+			},
+		)
+	}
+
+	endTime := time.Now().Truncate(time.Second)
+	startTime := endTime.Add(-autoscalingSpec.Window)
+
+	output, err := config.AWS.CloudWatch().GetMetricData(&cloudwatch.GetMetricDataInput{
+		EndTime:           &endTime,
+		StartTime:         &startTime,
+		MetricDataQueries: queries,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	results := make(map[string][]*float64, len(output.MetricDataResults))
+	for _, result := range output.MetricDataResults {
+		if result.Id != nil {
+			results[*result.Id] = result.Values
+		}
+	}
+
+	return results, nil
+}
+
+// Returns false if there are no values
+func sumValues(values []*float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, val := range values {
+		if val != nil {
+			total += *val
+		}
+	}
+	return total, true
+}
+
+// Percentiles can't be combined across periods, so the per-period values are weighted by their sample counts (which approximates the percentile over the window)
+// Returns false if there are no samples
+func weightedAverage(values []*float64, weights []*float64) (float64, bool) {
+	if len(values) == 0 || len(values) != len(weights) {
+		return 0, false
+	}
+
+	total := 0.0
+	totalWeight := 0.0
+	for i := range values {
+		if values[i] == nil || weights[i] == nil {
+			continue
+		}
+		total += *values[i] * *weights[i]
+		totalWeight += *weights[i]
+	}
+
+	if totalWeight == 0 {
+		return 0, false
+	}
+
+	return total / totalWeight, true
+}
@@ -18,12 +18,17 @@ package realtimeapi
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 )
 
 const (
-	ErrAPIUpdating = "realtimeapi.api_updating"
+	ErrAPIUpdating                      = "realtimeapi.api_updating"
+	ErrAPINotDeployed                   = "realtimeapi.api_not_deployed"
+	ErrAPINotScaledToZero               = "realtimeapi.api_not_scaled_to_zero"
+	ErrActivationTimeout                = "realtimeapi.activation_timeout"
+	ErrUnexpectedRequestMonitorResponse = "realtimeapi.unexpected_request_monitor_response"
 )
 
 func ErrorAPIUpdating(apiName string) error {
@@ -32,3 +37,31 @@ func ErrorAPIUpdating(apiName string) error {
 		Message: fmt.Sprintf("%s is updating (override with --force)", apiName),
 	})
 }
+
+func ErrorAPINotDeployed(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPINotDeployed,
+		Message: fmt.Sprintf("%s is not deployed", apiName),
+	})
+}
+
+func ErrorAPINotScaledToZero(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPINotScaledToZero,
+		Message: fmt.Sprintf("%s is not scaled to zero, so its requests are not handled by the activator", apiName),
+	})
+}
+
+func ErrorActivationTimeout(apiName string, timeout time.Duration) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrActivationTimeout,
+		Message: fmt.Sprintf("%s was scaled to zero and no replica became ready within %s; run `cortex get %s` to check its status", apiName, timeout.String(), apiName),
+	})
+}
+
+func ErrorUnexpectedRequestMonitorResponse(statusCode int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnexpectedRequestMonitorResponse,
+		Message: fmt.Sprintf("request monitor responded with unexpected status code %d", statusCode),
+	})
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	kcore "k8s.io/api/core/v1"
+)
+
+// inFlightRequestSource provides the autoscaler with the API-wide number of in-flight requests
+type inFlightRequestSource interface {
+	// Returns the average over the window, or nil if no metrics are available
+	avgInFlight(apiName string, window time.Duration) (*float64, error)
+}
+
+var _cloudWatchSource = &cloudWatchInFlightSource{}
+
+var _requestMonitorSource = &requestMonitorInFlightSource{
+	client:  &http.Client{Timeout: 2 * time.Second},
+	samples: make(map[string]map[time.Time]float64),
+}
+
+func getInFlightRequestSource() inFlightRequestSource {
+	if config.Cluster.AutoscalingMetricsSource == clusterconfig.RequestMonitorAutoscalingMetricsSource {
+		return _requestMonitorSource
+	}
+	return _cloudWatchSource
+}
+
+// cloudWatchInFlightSource reads the in-flight metric which each replica's request monitor publishes to CloudWatch
+type cloudWatchInFlightSource struct{}
+
+func (source *cloudWatchInFlightSource) avgInFlight(apiName string, window time.Duration) (*float64, error) {
+	endTime := time.Now().Truncate(time.Second)
+	startTime := endTime.Add(-2 * window)
+	metricsDataQuery := cloudwatch.GetMetricDataInput{
+		EndTime:   &endTime,
+		StartTime: &startTime,
+		MetricDataQueries: []*cloudwatch.MetricDataQuery{
+			{
+				Id:    aws.String("inflight"),
+				Label: aws.String("InFlight"),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(config.Cluster.ClusterName),
+						MetricName: aws.String("in-flight"),
+						Dimensions: []*cloudwatch.Dimension{
+							{
+								Name:  aws.String("apiName"),
+								Value: aws.String(apiName),
+							},
+						},
+					},
+					Stat:   aws.String("Sum"),
+					Period: aws.Int64(10),
+				},
+			},
+		},
+	}
+
+	output, err := config.AWS.CloudWatch().GetMetricData(&metricsDataQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(output.MetricDataResults) == 0 {
+		return nil, nil
+	}
+
+	timestampCounter := -1
+	for i, timeStamp := range output.MetricDataResults[0].Timestamps {
+		if endTime.Sub(*timeStamp) < 20*time.Second {
+			timestampCounter = i
+		} else {
+			break
+		}
+	}
+
+	if timestampCounter == -1 {
+		return nil, nil // no metrics were available in the last 2 tick intervals
+	}
+
+	steps := int(window.Nanoseconds() / spec.AutoscalingTickInterval.Nanoseconds())
+
+	endTimeStampCounter := libmath.MinInt(timestampCounter+steps, len(output.MetricDataResults[0].Timestamps))
+
+	values := output.MetricDataResults[0].Values[timestampCounter:endTimeStampCounter]
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	avg := 0.0
+	for _, val := range values {
+		avg += *val
+	}
+	avg = avg / float64(len(values))
+
+	return &avg, nil
+}
+
+// requestMonitorInFlightSource scrapes each ready replica's request monitor, and keeps the API-wide sums in memory to average them over the window
+type requestMonitorInFlightSource struct {
+	sync.Mutex
+	client  *http.Client
+	samples map[string]map[time.Time]float64 // apiName -> scrape time -> API-wide in-flight requests
+}
+
+type requestMonitorInFlightStats struct {
+	InFlight  *float64 `json:"in_flight"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+func (source *requestMonitorInFlightSource) avgInFlight(apiName string, window time.Duration) (*float64, error) {
+	total, err := source.scrape(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	source.Lock()
+	defer source.Unlock()
+
+	samples, ok := source.samples[apiName]
+	if !ok {
+		samples = make(map[time.Time]float64)
+		source.samples[apiName] = samples
+	}
+
+	if total != nil {
+		samples[time.Now()] = *total
+	}
+
+	for t := range samples {
+		if time.Since(t) > window {
+			delete(samples, t)
+		}
+	}
+
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	avg := 0.0
+	for _, val := range samples {
+		avg += val
+	}
+	avg = avg / float64(len(samples))
+
+	return &avg, nil
+}
+
+// Returns nil if none of the API's replicas have reported metrics in the last 2 tick intervals
+func (source *requestMonitorInFlightSource) scrape(apiName string) (*float64, error) {
+	pods, err := config.K8s.ListPodsByLabel("apiName", apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	var readyPods []kcore.Pod
+	for _, pod := range pods {
+		if k8s.IsPodReady(&pod) && pod.Status.PodIP != "" {
+			readyPods = append(readyPods, pod)
+		}
+	}
+
+	if len(readyPods) == 0 {
+		return nil, nil
+	}
+
+	inFlight := make([]*float64, len(readyPods))
+	fns := make([]func() error, len(readyPods))
+	for i := range readyPods {
+		localIdx := i
+		fns[i] = func() error {
+			var err error
+			inFlight[localIdx], err = source.scrapePod(&readyPods[localIdx])
+			return err
+		}
+	}
+
+	errs := parallel.Run(fns[0], fns[1:]...)
+
+	var total *float64
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("%s autoscaler: unable to scrape request monitor: %s", apiName, errors.Message(err))
+			continue
+		}
+		if inFlight[i] == nil {
+			continue
+		}
+		if total == nil {
+			total = new(float64)
+		}
+		*total += *inFlight[i]
+	}
+
+	if total == nil {
+		if err := errors.FirstError(errs...); err != nil {
+			return nil, err
+		}
+	}
+
+	return total, nil
+}
+
+func (source *requestMonitorInFlightSource) scrapePod(pod *kcore.Pod) (*float64, error) {
+	url := fmt.Sprintf("http://%s:%s/in-flight", pod.Status.PodIP, operator.RequestMonitorPortStr)
+
+	response, err := source.client.Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, pod.Name)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(ErrorUnexpectedRequestMonitorResponse(response.StatusCode), pod.Name)
+	}
+
+	var stats requestMonitorInFlightStats
+	if err := json.NewDecoder(response.Body).Decode(&stats); err != nil {
+		return nil, errors.Wrap(err, pod.Name)
+	}
+
+	if stats.InFlight == nil || time.Since(time.Unix(stats.Timestamp, 0)) > 2*spec.AutoscalingTickInterval {
+		return nil, nil
+	}
+
+	return stats.InFlight, nil
+}
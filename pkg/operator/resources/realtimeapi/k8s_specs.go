@@ -19,6 +19,7 @@ package realtimeapi
 import (
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
@@ -204,18 +205,28 @@ func serviceSpec(api *spec.API) *kcore.Service {
 	})
 }
 
-func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
+// When the API is scaled to zero, requests are routed to the operator's activator, which holds them until a replica is ready
+func virtualServiceSpec(api *spec.API, scaledToZero bool) *istioclientnetworking.VirtualService {
+	destination := k8s.Destination{
+		ServiceName: operator.K8sName(api.Name),
+		Weight:      100,
+		Port:        uint32(operator.DefaultPortInt32),
+	}
+	rewrite := "predict"
+
+	if scaledToZero {
+		destination.ServiceName = _activatorServiceName
+		destination.Port = uint32(operator.ActivatorPortInt32)
+		rewrite = urls.Join("activate", api.Name)
+	}
+
 	return k8s.VirtualService(&k8s.VirtualServiceSpec{
-		Name:     operator.K8sName(api.Name),
-		Gateways: []string{"apis-gateway"},
-		Destinations: []k8s.Destination{{
-			ServiceName: operator.K8sName(api.Name),
-			Weight:      100,
-			Port:        uint32(operator.DefaultPortInt32),
-		}},
-		ExactPath:   api.Networking.Endpoint,
-		Rewrite:     pointer.String("predict"),
-		Annotations: api.ToK8sAnnotations(),
+		Name:         operator.K8sName(api.Name),
+		Gateways:     []string{"apis-gateway"},
+		Destinations: []k8s.Destination{destination},
+		ExactPath:    api.Networking.Endpoint,
+		Rewrite:      pointer.String(rewrite),
+		Annotations:  api.ToK8sAnnotations(),
 		Labels: map[string]string{
 			"apiName":      api.Name,
 			"apiKind":      api.Kind.String(),
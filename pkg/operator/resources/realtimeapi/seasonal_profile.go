@@ -0,0 +1,56 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+)
+
+const _seasonalProfileSavePeriod = 5 * time.Minute
+
+// the profile is kept across updates to the API (and deleted along with the API's other files in the cluster's bucket)
+func seasonalProfileKey(apiName string) string {
+	return filepath.Join("apis", apiName, "autoscaler", "seasonal_profile.json")
+}
+
+// Returns an empty profile if the API doesn't have one yet
+func getSeasonalProfile(apiName string) (*autoscaler.SeasonalProfile, error) {
+	key := seasonalProfileKey(apiName)
+
+	exists, err := config.AWS.IsS3File(config.Cluster.Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return autoscaler.NewSeasonalProfile(), nil
+	}
+
+	profile := autoscaler.NewSeasonalProfile()
+	if err := config.AWS.ReadJSONFromS3(profile, config.Cluster.Bucket, key); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func saveSeasonalProfile(apiName string, profile *autoscaler.SeasonalProfile) error {
+	return config.AWS.UploadJSONToS3(profile, config.Cluster.Bucket, seasonalProfileKey(apiName))
+}
@@ -83,11 +83,13 @@ func apiStatus(deployment *kapps.Deployment, allPods []kcore.Pod) (*status.Statu
 		return nil, err
 	}
 
+	minReplicas, _ := autoscalingSpec.ReplicaBoundsAt(time.Now())
+
 	status := &status.Status{}
 	status.APIName = deployment.Labels["apiName"]
 	status.APIID = deployment.Labels["apiID"]
 	status.ReplicaCounts = getReplicaCounts(deployment, allPods)
-	status.Code = getStatusCode(&status.ReplicaCounts, autoscalingSpec.MinReplicas)
+	status.Code = getStatusCode(&status.ReplicaCounts, minReplicas)
 
 	return status, nil
 }
@@ -146,6 +148,10 @@ func addPodToReplicaCounts(pod *kcore.Pod, deployment *kapps.Deployment, counts
 }
 
 func getStatusCode(counts *status.ReplicaCounts, minReplicas int32) status.Code {
+	if counts.Requested == 0 && minReplicas == 0 {
+		return status.ScaledToZero
+	}
+
 	if counts.Updated.Ready >= counts.Requested {
 		return status.Live
 	}
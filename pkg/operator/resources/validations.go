@@ -156,63 +156,20 @@ func validateK8s(api *userconfig.API, virtualServices []istioclientnetworking.Vi
 	return nil
 }
 
-/*
-CPU Reservations:
-
-FluentD 200
-StatsD 100
-KubeProxy 100
-AWS cni 10
-Reserved (150 + 150) see eks.yaml for details
-*/
-var _cortexCPUReserve = kresource.MustParse("710m")
-
-/*
-Memory Reservations:
-
-FluentD 200
-StatsD 100
-Reserved (300 + 300 + 200) see eks.yaml for details
-*/
-var _cortexMemReserve = kresource.MustParse("1100Mi")
-
-var _nvidiaCPUReserve = kresource.MustParse("100m")
-var _nvidiaMemReserve = kresource.MustParse("100Mi")
-
-var _inferentiaCPUReserve = kresource.MustParse("100m")
-var _inferentiaMemReserve = kresource.MustParse("100Mi")
-
 func validateK8sCompute(compute *userconfig.Compute, maxMem kresource.Quantity) error {
-	maxMem.Sub(_cortexMemReserve)
-
-	maxCPU := config.Cluster.InstanceMetadata.CPU
-	maxCPU.Sub(_cortexCPUReserve)
-
-	maxGPU := config.Cluster.InstanceMetadata.GPU
-	if maxGPU > 0 {
-		// Reserve resources for nvidia device plugin daemonset
-		maxCPU.Sub(_nvidiaCPUReserve)
-		maxMem.Sub(_nvidiaMemReserve)
-	}
-
-	maxInf := config.Cluster.InstanceMetadata.Inf
-	if maxInf > 0 {
-		// Reserve resources for inferentia device plugin daemonset
-		maxCPU.Sub(_inferentiaCPUReserve)
-		maxMem.Sub(_inferentiaMemReserve)
-	}
+	capacity := operator.InstanceUserCapacity(maxMem)
 
-	if compute.CPU != nil && maxCPU.Cmp(compute.CPU.Quantity) < 0 {
-		return ErrorNoAvailableNodeComputeLimit("CPU", compute.CPU.String(), maxCPU.String())
+	if compute.CPU != nil && capacity.CPU.Cmp(compute.CPU.Quantity) < 0 {
+		return ErrorNoAvailableNodeComputeLimit("CPU", compute.CPU.String(), capacity.CPU.String())
 	}
-	if compute.Mem != nil && maxMem.Cmp(compute.Mem.Quantity) < 0 {
-		return ErrorNoAvailableNodeComputeLimit("memory", compute.Mem.String(), maxMem.String())
+	if compute.Mem != nil && capacity.Mem.Cmp(compute.Mem.Quantity) < 0 {
+		return ErrorNoAvailableNodeComputeLimit("memory", compute.Mem.String(), capacity.Mem.String())
 	}
-	if compute.GPU > maxGPU {
-		return ErrorNoAvailableNodeComputeLimit("GPU", fmt.Sprintf("%d", compute.GPU), fmt.Sprintf("%d", maxGPU))
+	if compute.GPU > capacity.GPU {
+		return ErrorNoAvailableNodeComputeLimit("GPU", fmt.Sprintf("%d", compute.GPU), fmt.Sprintf("%d", capacity.GPU))
 	}
-	if compute.Inf > maxInf {
-		return ErrorNoAvailableNodeComputeLimit("Inf", fmt.Sprintf("%d", compute.Inf), fmt.Sprintf("%d", maxInf))
+	if compute.Inf > capacity.Inf {
+		return ErrorNoAvailableNodeComputeLimit("Inf", fmt.Sprintf("%d", compute.Inf), fmt.Sprintf("%d", capacity.Inf))
 	}
 	return nil
 }
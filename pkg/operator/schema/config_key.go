@@ -23,8 +23,27 @@ const (
 	ItemListKey       = "item_list"
 	FilePathListerKey = "file_path_lister"
 	DelimitedFilesKey = "delimited_files"
+	CSVFilesKey       = "csv_files"
+	DelimiterKey      = "delimiter"
 	S3PathsKey        = "s3_paths"
 	IncludesKey       = "includes"
 	ExcludesKey       = "excludes"
 	WorkersKey        = "workers"
+	MaxRetriesKey     = "max_retries"
+	TimeoutKey        = "timeout"
+	OutputKey         = "output"
+	S3PathKey         = "s3_path"
+	FormatKey         = "format"
+	DependsOnKey      = "depends_on"
+	APINameKey        = "api_name"
+	JobIDKey          = "job_id"
+	LabelsKey         = "labels"
+	IdempotencyKeyKey = "idempotency_key"
+
+	// Job Schedule
+	NameKey              = "name"
+	ScheduleKey          = "schedule"
+	ConcurrencyPolicyKey = "concurrency_policy"
+	HistoryLimitKey      = "history_limit"
+	SubmissionKey        = "submission"
 )
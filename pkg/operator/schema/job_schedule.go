@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// what to do when a job schedule fires while a job which it previously submitted is still in progress
+const (
+	AllowConcurrencyPolicy   = "allow"   // submit the job regardless
+	SkipConcurrencyPolicy    = "skip"    // don't submit the job
+	ReplaceConcurrencyPolicy = "replace" // stop the jobs which are in progress, and submit the job
+)
+
+var ConcurrencyPolicies = []string{AllowConcurrencyPolicy, SkipConcurrencyPolicy, ReplaceConcurrencyPolicy}
+
+// JobSchedule submits a job to a Batch API on a cron schedule
+type JobSchedule struct {
+	Name              string        `json:"name"`
+	Schedule          string        `json:"schedule"` // a cron expression, evaluated in UTC
+	ConcurrencyPolicy string        `json:"concurrency_policy"`
+	HistoryLimit      int           `json:"history_limit"` // the number of runs to keep track of
+	Submission        JobSubmission `json:"submission"`
+	CreatedTime       time.Time     `json:"created_time"`
+}
+
+type JobScheduleRun struct {
+	ScheduledTime time.Time       `json:"scheduled_time"`
+	JobID         string          `json:"job_id,omitempty"`  // empty if the job was skipped or couldn't be submitted
+	Status        *status.JobCode `json:"status,omitempty"`  // the job's current status (not stored)
+	Skipped       bool            `json:"skipped,omitempty"` // whether the job was skipped due to the skip concurrency policy
+	Error         string          `json:"error,omitempty"`   // the reason that the job couldn't be submitted
+}
+
+type JobScheduleStatus struct {
+	JobSchedule
+	Runs []JobScheduleRun `json:"runs"` // the most recent run is first
+}
+
+type CreateJobScheduleResponse struct {
+	Message string `json:"message"`
+}
+
+type GetJobSchedulesResponse struct {
+	APIName   string              `json:"api_name"`
+	Schedules []JobScheduleStatus `json:"schedules"`
+}
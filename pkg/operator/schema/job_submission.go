@@ -43,9 +43,18 @@ type DelimitedFiles struct {
 	BatchSize int `json:"batch_size"`
 }
 
+type CSVFiles struct {
+	S3Lister
+	BatchSize  int    `json:"batch_size"`
+	Delimiter  string `json:"delimiter"`
+	LazyQuotes bool   `json:"lazy_quotes"`
+}
+
 type JobSubmission struct {
 	spec.RuntimeJobConfig
 	ItemList       *ItemList       `json:"item_list"`
 	FilePathLister *FilePathLister `json:"file_path_lister"`
 	DelimitedFiles *DelimitedFiles `json:"delimited_files"`
+	CSVFiles       *CSVFiles       `json:"csv_files"`
+	IdempotencyKey *string         `json:"idempotency_key"` // if a job was submitted with the same key recently, it is returned instead of submitting a new job
 }
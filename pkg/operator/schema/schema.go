@@ -17,6 +17,9 @@ limitations under the License.
 package schema
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/metrics"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
@@ -59,11 +62,12 @@ type GetAPIsResponse struct {
 }
 
 type RealtimeAPI struct {
-	Spec         spec.API        `json:"spec"`
-	Status       status.Status   `json:"status"`
-	Metrics      metrics.Metrics `json:"metrics"`
-	Endpoint     string          `json:"endpoint"`
-	DashboardURL string          `json:"dashboard_url"`
+	Spec                      spec.API                        `json:"spec"`
+	Status                    status.Status                   `json:"status"`
+	Metrics                   metrics.Metrics                 `json:"metrics"`
+	Endpoint                  string                          `json:"endpoint"`
+	DashboardURL              string                          `json:"dashboard_url"`
+	ActiveAutoscalingSchedule *userconfig.AutoscalingSchedule `json:"active_autoscaling_schedule"`
 }
 
 type TrafficSplitter struct {
@@ -89,6 +93,93 @@ type GetJobResponse struct {
 	Endpoint  string           `json:"endpoint"`
 }
 
+type ListJobsResponse struct {
+	APIName     string             `json:"api_name"`
+	JobStatuses []status.JobStatus `json:"job_statuses"`
+}
+
+// JobHistoryQuery filters and pages through an API's job history; the zero value of each filter matches all jobs
+type JobHistoryQuery struct {
+	Limit           int
+	After           string          // job id; only jobs which were submitted before this job are listed
+	Status          *status.JobCode // jobs which have this status
+	SubmittedAfter  *time.Time
+	SubmittedBefore *time.Time
+	LabelKey        string // jobs which have this label (with LabelValue as its value, if LabelValue is set)
+	LabelValue      string
+}
+
+type JobHistoryResponse struct {
+	APIName     string             `json:"api_name"`
+	JobStatuses []status.JobStatus `json:"job_statuses"` // most recently submitted first
+	NextPage    string             `json:"next_page"`    // the job id to list the next page after; empty if there are no more matching jobs (may be set even if fewer jobs than the limit were found, since the number of jobs searched per request is capped)
+}
+
+type FailedBatch struct {
+	ID      string          `json:"id"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type GetFailedBatchesResponse struct {
+	spec.JobKey
+	MaxRetries    *int          `json:"max_retries"`
+	FailedBatches []FailedBatch `json:"failed_batches"`
+}
+
+type GetAutoscalingResponse struct {
+	APIName   string               `json:"api_name"`
+	Decisions []AutoscalerDecision `json:"decisions"` // oldest first
+}
+
+// AutoscalerDecision records the inputs and outputs of one of a RealtimeAPI autoscaler's ticks
+type AutoscalerDecision struct {
+	Timestamp                   time.Time                        `json:"timestamp"`
+	Message                     string                           `json:"message"` // set if the tick ended before a recommendation was made
+	AvgInFlight                 *float64                         `json:"avg_in_flight"`
+	TargetReplicaConcurrency    float64                          `json:"target_replica_concurrency"`
+	Policies                    []AutoscalerPolicyRecommendation `json:"policies"`
+	RawRecommendation           float64                          `json:"raw_recommendation"`
+	CurrentReplicas             int32                            `json:"current_replicas"`
+	DownscaleFactorFloor        int32                            `json:"downscale_factor_floor"`
+	UpscaleFactorCeil           int32                            `json:"upscale_factor_ceil"`
+	ActiveSchedule              *string                          `json:"active_schedule"`
+	PredictedInFlight           *float64                         `json:"predicted_in_flight"`
+	PredictiveFloor             *int32                           `json:"predictive_floor"`
+	MinReplicas                 int32                            `json:"min_replicas"`
+	MaxReplicas                 int32                            `json:"max_replicas"`
+	Recommendation              int32                            `json:"recommendation"`
+	DownscaleStabilizationFloor *int32                           `json:"downscale_stabilization_floor"`
+	UpscaleStabilizationCeil    *int32                           `json:"upscale_stabilization_ceil"`
+	IdleTime                    time.Duration                    `json:"idle_time"`
+	Request                     int32                            `json:"request"`
+}
+
+// AutoscalerPolicyRecommendation is the raw (unrounded, unbounded) number of replicas recommended by one of the optional autoscaling policies
+type AutoscalerPolicyRecommendation struct {
+	Policy            string  `json:"policy"`
+	Metric            float64 `json:"metric"`
+	Target            float64 `json:"target"`
+	RawRecommendation float64 `json:"raw_recommendation"`
+}
+
+// EndpointToken is a bearer token which can be used to make requests to a Batch API's endpoint (if its endpoint_auth is "token")
+type EndpointToken struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	CreatedTime time.Time `json:"created_time"`
+}
+
+type CreateEndpointTokenResponse struct {
+	EndpointToken
+	Token string `json:"token"` // the token is only returned when it is created
+}
+
+type GetEndpointTokensResponse struct {
+	APIName      string                      `json:"api_name"`
+	EndpointAuth userconfig.EndpointAuthType `json:"endpoint_auth"`
+	Tokens       []EndpointToken             `json:"tokens"`
+}
+
 type DeleteResponse struct {
 	Message string `json:"message"`
 }
@@ -97,6 +188,18 @@ type RefreshResponse struct {
 	Message string `json:"message"`
 }
 
+type PauseJobResponse struct {
+	Message string `json:"message"`
+}
+
+type ResumeJobResponse struct {
+	Message string `json:"message"`
+}
+
+type ResizeJobResponse struct {
+	Message string `json:"message"`
+}
+
 type ErrorResponse struct {
 	Kind    string `json:"kind"`
 	Message string `json:"message"`
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+type AutoscalingMetricsSource int
+
+const (
+	UnknownAutoscalingMetricsSource AutoscalingMetricsSource = iota
+	CloudWatchAutoscalingMetricsSource
+	RequestMonitorAutoscalingMetricsSource
+)
+
+var _autoscalingMetricsSources = []string{
+	"unknown",
+	"cloudwatch",
+	"request_monitor",
+}
+
+func AutoscalingMetricsSourceFromString(s string) AutoscalingMetricsSource {
+	for i := 0; i < len(_autoscalingMetricsSources); i++ {
+		if s == _autoscalingMetricsSources[i] {
+			return AutoscalingMetricsSource(i)
+		}
+	}
+	return UnknownAutoscalingMetricsSource
+}
+
+func AutoscalingMetricsSourceStrings() []string {
+	return _autoscalingMetricsSources[1:]
+}
+
+func (t AutoscalingMetricsSource) String() string {
+	return _autoscalingMetricsSources[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t AutoscalingMetricsSource) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *AutoscalingMetricsSource) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_autoscalingMetricsSources); i++ {
+		if enum == _autoscalingMetricsSources[i] {
+			*t = AutoscalingMetricsSource(i)
+			return nil
+		}
+	}
+
+	*t = UnknownAutoscalingMetricsSource
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *AutoscalingMetricsSource) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t AutoscalingMetricsSource) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
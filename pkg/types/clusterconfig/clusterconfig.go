@@ -52,42 +52,44 @@ var (
 )
 
 type Config struct {
-	InstanceType               *string            `json:"instance_type" yaml:"instance_type"`
-	MinInstances               *int64             `json:"min_instances" yaml:"min_instances"`
-	MaxInstances               *int64             `json:"max_instances" yaml:"max_instances"`
-	InstanceVolumeSize         int64              `json:"instance_volume_size" yaml:"instance_volume_size"`
-	InstanceVolumeType         VolumeType         `json:"instance_volume_type" yaml:"instance_volume_type"`
-	InstanceVolumeIOPS         *int64             `json:"instance_volume_iops" yaml:"instance_volume_iops"`
-	Tags                       map[string]string  `json:"tags" yaml:"tags"`
-	Spot                       *bool              `json:"spot" yaml:"spot"`
-	SpotConfig                 *SpotConfig        `json:"spot_config" yaml:"spot_config"`
-	ClusterName                string             `json:"cluster_name" yaml:"cluster_name"`
-	Region                     *string            `json:"region" yaml:"region"`
-	AvailabilityZones          []string           `json:"availability_zones" yaml:"availability_zones"`
-	SSLCertificateARN          *string            `json:"ssl_certificate_arn,omitempty" yaml:"ssl_certificate_arn,omitempty"`
-	Bucket                     string             `json:"bucket" yaml:"bucket"`
-	LogGroup                   string             `json:"log_group" yaml:"log_group"`
-	SubnetVisibility           SubnetVisibility   `json:"subnet_visibility" yaml:"subnet_visibility"`
-	NATGateway                 NATGateway         `json:"nat_gateway" yaml:"nat_gateway"`
-	APILoadBalancerScheme      LoadBalancerScheme `json:"api_load_balancer_scheme" yaml:"api_load_balancer_scheme"`
-	OperatorLoadBalancerScheme LoadBalancerScheme `json:"operator_load_balancer_scheme" yaml:"operator_load_balancer_scheme"`
-	APIGatewaySetting          APIGatewaySetting  `json:"api_gateway" yaml:"api_gateway"`
-	Telemetry                  bool               `json:"telemetry" yaml:"telemetry"`
-	ImageOperator              string             `json:"image_operator" yaml:"image_operator"`
-	ImageManager               string             `json:"image_manager" yaml:"image_manager"`
-	ImageDownloader            string             `json:"image_downloader" yaml:"image_downloader"`
-	ImageRequestMonitor        string             `json:"image_request_monitor" yaml:"image_request_monitor"`
-	ImageClusterAutoscaler     string             `json:"image_cluster_autoscaler" yaml:"image_cluster_autoscaler"`
-	ImageMetricsServer         string             `json:"image_metrics_server" yaml:"image_metrics_server"`
-	ImageInferentia            string             `json:"image_inferentia" yaml:"image_inferentia"`
-	ImageNeuronRTD             string             `json:"image_neuron_rtd" yaml:"image_neuron_rtd"`
-	ImageNvidia                string             `json:"image_nvidia" yaml:"image_nvidia"`
-	ImageFluentd               string             `json:"image_fluentd" yaml:"image_fluentd"`
-	ImageStatsd                string             `json:"image_statsd" yaml:"image_statsd"`
-	ImageIstioProxy            string             `json:"image_istio_proxy" yaml:"image_istio_proxy"`
-	ImageIstioPilot            string             `json:"image_istio_pilot" yaml:"image_istio_pilot"`
-	ImageIstioCitadel          string             `json:"image_istio_citadel" yaml:"image_istio_citadel"`
-	ImageIstioGalley           string             `json:"image_istio_galley" yaml:"image_istio_galley"`
+	InstanceType               *string                  `json:"instance_type" yaml:"instance_type"`
+	MinInstances               *int64                   `json:"min_instances" yaml:"min_instances"`
+	MaxInstances               *int64                   `json:"max_instances" yaml:"max_instances"`
+	InstanceVolumeSize         int64                    `json:"instance_volume_size" yaml:"instance_volume_size"`
+	InstanceVolumeType         VolumeType               `json:"instance_volume_type" yaml:"instance_volume_type"`
+	InstanceVolumeIOPS         *int64                   `json:"instance_volume_iops" yaml:"instance_volume_iops"`
+	Tags                       map[string]string        `json:"tags" yaml:"tags"`
+	Spot                       *bool                    `json:"spot" yaml:"spot"`
+	SpotConfig                 *SpotConfig              `json:"spot_config" yaml:"spot_config"`
+	ClusterName                string                   `json:"cluster_name" yaml:"cluster_name"`
+	Region                     *string                  `json:"region" yaml:"region"`
+	AvailabilityZones          []string                 `json:"availability_zones" yaml:"availability_zones"`
+	SSLCertificateARN          *string                  `json:"ssl_certificate_arn,omitempty" yaml:"ssl_certificate_arn,omitempty"`
+	Bucket                     string                   `json:"bucket" yaml:"bucket"`
+	LogGroup                   string                   `json:"log_group" yaml:"log_group"`
+	SubnetVisibility           SubnetVisibility         `json:"subnet_visibility" yaml:"subnet_visibility"`
+	NATGateway                 NATGateway               `json:"nat_gateway" yaml:"nat_gateway"`
+	APILoadBalancerScheme      LoadBalancerScheme       `json:"api_load_balancer_scheme" yaml:"api_load_balancer_scheme"`
+	OperatorLoadBalancerScheme LoadBalancerScheme       `json:"operator_load_balancer_scheme" yaml:"operator_load_balancer_scheme"`
+	APIGatewaySetting          APIGatewaySetting        `json:"api_gateway" yaml:"api_gateway"`
+	AutoscalingMetricsSource   AutoscalingMetricsSource `json:"autoscaling_metrics_source" yaml:"autoscaling_metrics_source"`
+	BatchJobRetentionDays      *int64                   `json:"batch_job_retention_days" yaml:"batch_job_retention_days"`
+	Telemetry                  bool                     `json:"telemetry" yaml:"telemetry"`
+	ImageOperator              string                   `json:"image_operator" yaml:"image_operator"`
+	ImageManager               string                   `json:"image_manager" yaml:"image_manager"`
+	ImageDownloader            string                   `json:"image_downloader" yaml:"image_downloader"`
+	ImageRequestMonitor        string                   `json:"image_request_monitor" yaml:"image_request_monitor"`
+	ImageClusterAutoscaler     string                   `json:"image_cluster_autoscaler" yaml:"image_cluster_autoscaler"`
+	ImageMetricsServer         string                   `json:"image_metrics_server" yaml:"image_metrics_server"`
+	ImageInferentia            string                   `json:"image_inferentia" yaml:"image_inferentia"`
+	ImageNeuronRTD             string                   `json:"image_neuron_rtd" yaml:"image_neuron_rtd"`
+	ImageNvidia                string                   `json:"image_nvidia" yaml:"image_nvidia"`
+	ImageFluentd               string                   `json:"image_fluentd" yaml:"image_fluentd"`
+	ImageStatsd                string                   `json:"image_statsd" yaml:"image_statsd"`
+	ImageIstioProxy            string                   `json:"image_istio_proxy" yaml:"image_istio_proxy"`
+	ImageIstioPilot            string                   `json:"image_istio_pilot" yaml:"image_istio_pilot"`
+	ImageIstioCitadel          string                   `json:"image_istio_citadel" yaml:"image_istio_citadel"`
+	ImageIstioGalley           string                   `json:"image_istio_galley" yaml:"image_istio_galley"`
 }
 
 type SpotConfig struct {
@@ -346,6 +348,23 @@ var UserValidation = &cr.StructValidation{
 				return APIGatewaySettingFromString(str), nil
 			},
 		},
+		{
+			StructField: "AutoscalingMetricsSource",
+			StringValidation: &cr.StringValidation{
+				AllowedValues: AutoscalingMetricsSourceStrings(),
+				Default:       CloudWatchAutoscalingMetricsSource.String(),
+			},
+			Parser: func(str string) (interface{}, error) {
+				return AutoscalingMetricsSourceFromString(str), nil
+			},
+		},
+		{
+			StructField: "BatchJobRetentionDays",
+			Int64PtrValidation: &cr.Int64PtrValidation{
+				GreaterThan:       pointer.Int64(0),
+				AllowExplicitNull: true,
+			},
+		},
 		{
 			StructField: "ImageOperator",
 			StringValidation: &cr.StringValidation{
@@ -1131,6 +1150,10 @@ func (cc *Config) UserTable() table.KeyValuePairs {
 	items.Add(APILoadBalancerSchemeUserKey, cc.APILoadBalancerScheme)
 	items.Add(OperatorLoadBalancerSchemeUserKey, cc.OperatorLoadBalancerScheme)
 	items.Add(APIGatewaySettingUserKey, cc.APIGatewaySetting)
+	items.Add(AutoscalingMetricsSourceUserKey, cc.AutoscalingMetricsSource)
+	if cc.BatchJobRetentionDays != nil {
+		items.Add(BatchJobRetentionDaysUserKey, *cc.BatchJobRetentionDays)
+	}
 	items.Add(TelemetryUserKey, cc.Telemetry)
 	items.Add(ImageOperatorUserKey, cc.ImageOperator)
 	items.Add(ImageManagerUserKey, cc.ImageManager)
@@ -43,6 +43,8 @@ const (
 	APILoadBalancerSchemeKey               = "api_load_balancer_scheme"
 	OperatorLoadBalancerSchemeKey          = "operator_load_balancer_scheme"
 	APIGatewaySettingKey                   = "api_gateway"
+	AutoscalingMetricsSourceKey            = "autoscaling_metrics_source"
+	BatchJobRetentionDaysKey               = "batch_job_retention_days"
 	TelemetryKey                           = "telemetry"
 	ImageOperatorKey                       = "image_operator"
 	ImageManagerKey                        = "image_manager"
@@ -87,6 +89,8 @@ const (
 	APILoadBalancerSchemeUserKey               = "api load balancer scheme"
 	OperatorLoadBalancerSchemeUserKey          = "operator load balancer scheme"
 	APIGatewaySettingUserKey                   = "api gateway"
+	AutoscalingMetricsSourceUserKey            = "autoscaling metrics source"
+	BatchJobRetentionDaysUserKey               = "batch job retention (days)"
 	TelemetryUserKey                           = "telemetry"
 	ImageOperatorUserKey                       = "operator image"
 	ImageManagerUserKey                        = "manager image"
@@ -20,6 +20,7 @@ type BatchMetrics struct {
 	Succeeded           int      `json:"succeeded"`
 	Failed              int      `json:"failed"`
 	AverageTimePerBatch *float64 `json:"average_time_per_batch"`
+	OutputItems         int      `json:"-"` // the number of items written to the job's output (reported in the job's status)
 }
 
 func (batchMetrics BatchMetrics) TotalCompleted() int {
@@ -37,4 +38,5 @@ func (batchMetrics *BatchMetrics) MergeInPlace(right BatchMetrics) {
 	batchMetrics.AverageTimePerBatch = mergeAvg(batchMetrics.AverageTimePerBatch, batchMetrics.TotalCompleted(), right.AverageTimePerBatch, right.TotalCompleted())
 	batchMetrics.Succeeded = batchMetrics.Succeeded + right.Succeeded
 	batchMetrics.Failed = batchMetrics.Failed + right.Failed
+	batchMetrics.OutputItems = batchMetrics.OutputItems + right.OutputItems
 }
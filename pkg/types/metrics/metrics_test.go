@@ -160,3 +160,25 @@ func TestAPIMetricsMerge(t *testing.T) {
 
 	require.Equal(t, mergedAPIMetrics, apiMetrics.Merge(apiMetrics))
 }
+
+func TestBatchMetricsMerge(t *testing.T) {
+	require.Equal(t, BatchMetrics{}, BatchMetrics{}.Merge(BatchMetrics{}))
+
+	batchMetrics := BatchMetrics{
+		Succeeded:           3,
+		Failed:              1,
+		AverageTimePerBatch: pointer.Float64(2),
+		OutputItems:         30,
+	}
+
+	mergedBatchMetrics := BatchMetrics{
+		Succeeded:           6,
+		Failed:              2,
+		AverageTimePerBatch: pointer.Float64(2),
+		OutputItems:         60,
+	}
+
+	require.Equal(t, batchMetrics, batchMetrics.Merge(BatchMetrics{}))
+	require.Equal(t, batchMetrics, BatchMetrics{}.Merge(batchMetrics))
+	require.Equal(t, mergedBatchMetrics, batchMetrics.Merge(batchMetrics))
+}
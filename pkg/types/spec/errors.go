@@ -38,6 +38,7 @@ const (
 	ErrSpecifyAllOrNone                     = "spec.specify_all_or_none"
 	ErrOneOfPrerequisitesNotDefined         = "spec.one_of_prerequisites_not_defined"
 	ErrConfigGreaterThanOtherConfig         = "spec.config_greater_than_other_config"
+	ErrFieldRequiresGPU                     = "spec.field_requires_gpu"
 	ErrMinReplicasGreaterThanMax            = "spec.min_replicas_greater_than_max"
 	ErrInitReplicasGreaterThanMax           = "spec.init_replicas_greater_than_max"
 	ErrInitReplicasLessThanMin              = "spec.init_replicas_less_than_min"
@@ -156,6 +157,13 @@ func ErrorConfigGreaterThanOtherConfig(tooBigKey string, tooBigVal interface{},
 	})
 }
 
+func ErrorFieldRequiresGPU(fieldKey string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldRequiresGPU,
+		Message: fmt.Sprintf("%s can only be specified if %s is greater than 0", fieldKey, userconfig.GPUKey),
+	})
+}
+
 func ErrorMinReplicasGreaterThanMax(min int32, max int32) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrMinReplicasGreaterThanMax,
@@ -30,6 +30,11 @@ func MonotonicallyDecreasingID() string {
 	idGenMutex.Lock()
 	defer idGenMutex.Unlock()
 
-	i := math.MaxInt64 - time.Now().UnixNano()
+	return MonotonicallyDecreasingIDForTime(time.Now())
+}
+
+// Returns the ID which would be generated at t; IDs which are generated before t are greater, and IDs which are generated after t are smaller
+func MonotonicallyDecreasingIDForTime(t time.Time) string {
+	i := math.MaxInt64 - t.UnixNano()
 	return fmt.Sprintf("%x", i)
 }
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonotonicallyDecreasingIDForTime(t *testing.T) {
+	t1 := time.Date(2020, 9, 7, 8, 30, 0, 0, time.UTC)
+
+	ids := []string{
+		MonotonicallyDecreasingIDForTime(t1),
+		MonotonicallyDecreasingIDForTime(t1.Add(time.Nanosecond)),
+		MonotonicallyDecreasingIDForTime(t1.Add(time.Hour)),
+		MonotonicallyDecreasingIDForTime(t1.Add(100 * 365 * 24 * time.Hour)),
+	}
+
+	for i := range ids {
+		// ids are compared as strings (e.g. when listing s3 keys), which requires them to have the same length
+		require.Len(t, ids[i], 16)
+		if i > 0 {
+			require.True(t, ids[i-1] > ids[i])
+		}
+	}
+
+	require.Equal(t, ids[0], MonotonicallyDecreasingIDForTime(t1))
+	require.True(t, MonotonicallyDecreasingID() < ids[0])
+}
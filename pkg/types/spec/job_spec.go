@@ -50,8 +50,31 @@ func (j JobKey) K8sName() string {
 }
 
 type RuntimeJobConfig struct {
-	Workers int                    `json:"workers"`
-	Config  map[string]interface{} `json:"config"`
+	Workers    int                    `json:"workers"`
+	Config     map[string]interface{} `json:"config"`
+	MaxRetries *int                   `json:"max_retries"` // if set, failed batches are retried and then moved to the job's dead letter queue
+	Timeout    *int                   `json:"timeout"`     // seconds; the visibility timeout of a batch, which workers extend while they are processing it
+	Output     *JobOutput             `json:"output"`
+	DependsOn  []JobKey               `json:"depends_on"` // the job waits for these jobs to succeed before it starts
+	Labels     map[string]string      `json:"labels"`     // user-defined; the job history can be filtered by label
+}
+
+const (
+	JSONLinesJobOutputFormat = "json_lines" // one file per batch, with one line per item of the predictor's response
+	JSONJobOutputFormat      = "json"       // one file per batch, containing the predictor's response
+)
+
+var JobOutputFormats = []string{JSONLinesJobOutputFormat, JSONJobOutputFormat}
+
+// JobOutput configures where the runtime writes the responses of the predictor
+type JobOutput struct {
+	S3Path string `json:"s3_path"` // s3://<bucket>/<prefix>
+	Format string `json:"format"`
+}
+
+// e.g. s3://<bucket>/<prefix>/<job_id>/
+func (o JobOutput) JobS3Path(jobID string) string {
+	return s.EnsureSuffix(s.EnsureSuffix(o.S3Path, "/")+jobID, "/")
 }
 
 type Job struct {
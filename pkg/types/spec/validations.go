@@ -288,6 +288,18 @@ func networkingValidation(
 			},
 		},
 	}
+	if kind == userconfig.BatchAPIKind {
+		structFieldValidation = append(structFieldValidation, &cr.StructFieldValidation{
+			StructField: "EndpointAuth",
+			StringValidation: &cr.StringValidation{
+				AllowedValues: userconfig.EndpointAuthTypeStrings(),
+				Default:       userconfig.NoneEndpointAuthType.String(),
+			},
+			Parser: func(str string) (interface{}, error) {
+				return userconfig.EndpointAuthTypeFromString(str), nil
+			},
+		})
+	}
 	if kind == userconfig.RealtimeAPIKind {
 		structFieldValidation = append(structFieldValidation, &cr.StructFieldValidation{
 			StructField: "LocalPort",
@@ -366,8 +378,8 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 				{
 					StructField: "MinReplicas",
 					Int32Validation: &cr.Int32Validation{
-						Default:     1,
-						GreaterThan: pointer.Int32(0),
+						Default:              1,
+						GreaterThanOrEqualTo: pointer.Int32(0),
 					},
 				},
 				{
@@ -381,7 +393,7 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 					StructField:  "InitReplicas",
 					DefaultField: "MinReplicas",
 					Int32Validation: &cr.Int32Validation{
-						GreaterThan: pointer.Int32(0),
+						GreaterThanOrEqualTo: pointer.Int32(0),
 					},
 				},
 				{
@@ -390,6 +402,26 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 						GreaterThan: pointer.Float64(0),
 					},
 				},
+				{
+					StructField:         "TargetP95Latency",
+					StringPtrValidation: &cr.StringPtrValidation{},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThan: pointer.Duration(libtime.MustParseDuration("0s")),
+					}),
+				},
+				{
+					StructField: "TargetRequestsPerSecond",
+					Float64PtrValidation: &cr.Float64PtrValidation{
+						GreaterThan: pointer.Float64(0),
+					},
+				},
+				{
+					StructField: "TargetGPUUtilization",
+					Float64PtrValidation: &cr.Float64PtrValidation{
+						GreaterThan:       pointer.Float64(0),
+						LessThanOrEqualTo: pointer.Float64(100),
+					},
+				},
 				{
 					StructField: "MaxReplicaConcurrency",
 					Int64Validation: &cr.Int64Validation{
@@ -456,6 +488,32 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 						GreaterThanOrEqualTo: pointer.Float64(0),
 					},
 				},
+				{
+					StructField: "ScaleToZeroPeriod",
+					StringValidation: &cr.StringValidation{
+						Default: "10m",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: &AutoscalingTickInterval,
+					}),
+				},
+				{
+					StructField: "PredictiveScaling",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField: "PredictiveScalingLeadTime",
+					StringValidation: &cr.StringValidation{
+						Default: "15m",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("0s")),
+						LessThanOrEqualTo:    pointer.Duration(libtime.MustParseDuration("24h")),
+					}),
+				},
+				autoscalingSchedulesValidation(),
 			},
 		},
 	}
@@ -558,6 +616,48 @@ func serverSideBatchingValidation() *cr.StructFieldValidation {
 	}
 }
 
+func autoscalingSchedulesValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Schedules",
+		StructListValidation: &cr.StructListValidation{
+			Required:         false,
+			TreatNullAsEmpty: true,
+			StructValidation: &cr.StructValidation{
+				StructFieldValidations: []*cr.StructFieldValidation{
+					{
+						StructField: "Schedule",
+						StringValidation: &cr.StringValidation{
+							Required: true,
+						},
+					},
+					{
+						StructField: "Duration",
+						StringValidation: &cr.StringValidation{
+							Required: true,
+						},
+						Parser: cr.DurationParser(&cr.DurationValidation{
+							GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1m")),
+							LessThanOrEqualTo:    pointer.Duration(libtime.MustParseDuration("168h")),
+						}),
+					},
+					{
+						StructField: "MinReplicas",
+						Int32PtrValidation: &cr.Int32PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int32(0),
+						},
+					},
+					{
+						StructField: "MaxReplicas",
+						Int32PtrValidation: &cr.Int32PtrValidation{
+							GreaterThan: pointer.Int32(0),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func surgeOrUnavailableValidator(str string) (string, error) {
 	if strings.HasSuffix(str, "%") {
 		parsed, ok := s.ParseInt32(strings.TrimSuffix(str, "%"))
@@ -672,8 +772,8 @@ func ValidateAPI(
 	}
 
 	if api.Autoscaling != nil { // should only be nil for local provider
-		if err := validateAutoscaling(api); err != nil {
-			return errors.Wrap(err, userconfig.AutoscalingKey)
+		if err := ValidateAutoscaling(api); err != nil {
+			return err
 		}
 	}
 
@@ -1131,6 +1231,14 @@ func validatePythonPath(predictor *userconfig.Predictor, projectFiles ProjectFil
 	return nil
 }
 
+// ValidateAutoscaling also sets the autoscaling defaults which depend on the rest of the API's configuration
+func ValidateAutoscaling(api *userconfig.API) error {
+	if err := validateAutoscaling(api); err != nil {
+		return errors.Wrap(err, userconfig.AutoscalingKey)
+	}
+	return nil
+}
+
 func validateAutoscaling(api *userconfig.API) error {
 	autoscaling := api.Autoscaling
 	predictor := api.Predictor
@@ -1143,6 +1251,10 @@ func validateAutoscaling(api *userconfig.API) error {
 		return ErrorConfigGreaterThanOtherConfig(userconfig.TargetReplicaConcurrencyKey, *autoscaling.TargetReplicaConcurrency, userconfig.MaxReplicaConcurrencyKey, autoscaling.MaxReplicaConcurrency)
 	}
 
+	if autoscaling.TargetGPUUtilization != nil && api.Compute.GPU == 0 {
+		return ErrorFieldRequiresGPU(userconfig.TargetGPUUtilizationKey)
+	}
+
 	if autoscaling.MinReplicas > autoscaling.MaxReplicas {
 		return ErrorMinReplicasGreaterThanMax(autoscaling.MinReplicas, autoscaling.MaxReplicas)
 	}
@@ -1155,6 +1267,28 @@ func validateAutoscaling(api *userconfig.API) error {
 		return ErrorInitReplicasLessThanMin(autoscaling.InitReplicas, autoscaling.MinReplicas)
 	}
 
+	for i, schedule := range autoscaling.Schedules {
+		if err := schedule.ParseSchedule(); err != nil {
+			return errors.Wrap(err, userconfig.SchedulesKey, s.Index(i), userconfig.ScheduleKey)
+		}
+
+		if schedule.MinReplicas == nil && schedule.MaxReplicas == nil {
+			return errors.Wrap(ErrorOneOfPrerequisitesNotDefined(userconfig.ScheduleKey, userconfig.MinReplicasKey, userconfig.MaxReplicasKey), userconfig.SchedulesKey, s.Index(i))
+		}
+
+		minReplicas := autoscaling.MinReplicas
+		if schedule.MinReplicas != nil {
+			minReplicas = *schedule.MinReplicas
+		}
+		maxReplicas := autoscaling.MaxReplicas
+		if schedule.MaxReplicas != nil {
+			maxReplicas = *schedule.MaxReplicas
+		}
+		if minReplicas > maxReplicas {
+			return errors.Wrap(ErrorMinReplicasGreaterThanMax(minReplicas, maxReplicas), userconfig.SchedulesKey, s.Index(i))
+		}
+	}
+
 	if api.Compute.Inf > 0 {
 		numNeuronCores := api.Compute.Inf * consts.NeuronCoresPerInf
 		processesPerReplica := int64(predictor.ProcessesPerReplica)
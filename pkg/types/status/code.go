@@ -26,6 +26,7 @@ const (
 	OOM
 	Live
 	Updating
+	ScaledToZero
 )
 
 var _codes = []string{
@@ -36,9 +37,10 @@ var _codes = []string{
 	"status_oom",
 	"status_live",
 	"status_updating",
+	"status_scaled_to_zero",
 }
 
-var _ = [1]int{}[int(Updating)-(len(_codes)-1)] // Ensure list length matches
+var _ = [1]int{}[int(ScaledToZero)-(len(_codes)-1)] // Ensure list length matches
 
 var _codeMessages = []string{
 	"unknown",               // Unknown
@@ -48,9 +50,10 @@ var _codeMessages = []string{
 	"error (out of memory)", // OOM
 	"live",                  // Live
 	"updating",              // Updating
+	"scaled to zero",        // ScaledToZero
 }
 
-var _ = [1]int{}[int(Updating)-(len(_codeMessages)-1)] // Ensure list length matches
+var _ = [1]int{}[int(ScaledToZero)-(len(_codeMessages)-1)] // Ensure list length matches
 
 func (code Code) String() string {
 	if int(code) < 0 || int(code) >= len(_codes) {
@@ -20,27 +20,33 @@ type JobCode int
 
 const (
 	JobUnknown JobCode = iota
+	JobWaiting
 	JobEnqueuing
 	JobRunning
+	JobPaused
 	JobEnqueueFailed
 	JobCompletedWithFailures
 	JobSucceeded
 	JobUnexpectedError
 	JobWorkerError
 	JobWorkerOOM
+	JobDependencyFailed
 	JobStopped
 )
 
 var _jobCodes = []string{
 	"status_unknown",
+	"status_waiting",
 	"status_enqueuing",
 	"status_running",
+	"status_paused",
 	"status_enqueue_failed",
 	"status_completed_with_failures",
 	"status_succeeded",
 	"status_unexpected_error",
 	"status_worker_error",
 	"status_worker_oom",
+	"status_dependency_failed",
 	"status_stopped",
 }
 
@@ -48,25 +54,50 @@ var _ = [1]int{}[int(JobStopped)-(len(_jobCodes)-1)] // Ensure list length match
 
 var _jobCodeMessages = []string{
 	"unknown",
+	"waiting for upstream jobs",
 	"enqueuing",
 	"running",
+	"paused",
 	"failed while enqueuing",
 	"completed with failures",
 	"succeeded",
 	"unexpected error",
 	"worker error",
 	"out of memory",
+	"upstream job failed",
 	"stopped",
 }
 
 var _ = [1]int{}[int(JobStopped)-(len(_jobCodeMessages)-1)] // Ensure list length matches
 
+// the statuses which a job can move to from each in progress status (a new job moves from JobUnknown); completed statuses are final
+var _jobCodeTransitions = map[JobCode][]JobCode{
+	JobUnknown:   {JobWaiting, JobEnqueuing},
+	JobWaiting:   {JobEnqueuing, JobDependencyFailed, JobUnexpectedError, JobStopped},
+	JobEnqueuing: {JobRunning, JobEnqueueFailed, JobUnexpectedError, JobStopped},
+	JobRunning:   {JobPaused, JobCompletedWithFailures, JobSucceeded, JobUnexpectedError, JobWorkerError, JobWorkerOOM, JobStopped},
+	JobPaused:    {JobRunning, JobUnexpectedError, JobStopped},
+}
+
+// CanTransitionTo returns whether a job with this status can be moved to the next status (setting the same status again is allowed)
+func (code JobCode) CanTransitionTo(next JobCode) bool {
+	if code == next {
+		return true
+	}
+	for _, allowed := range _jobCodeTransitions[code] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 func (code JobCode) IsInProgress() bool {
-	return code == JobEnqueuing || code == JobRunning
+	return code == JobWaiting || code == JobEnqueuing || code == JobRunning || code == JobPaused
 }
 
 func (code JobCode) IsCompleted() bool {
-	return code == JobEnqueueFailed || code == JobCompletedWithFailures || code == JobSucceeded || code == JobUnexpectedError || code == JobWorkerError || code == JobWorkerOOM || code == JobStopped
+	return code == JobEnqueueFailed || code == JobCompletedWithFailures || code == JobSucceeded || code == JobUnexpectedError || code == JobWorkerError || code == JobWorkerOOM || code == JobDependencyFailed || code == JobStopped
 }
 
 func (code JobCode) String() string {
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionTo(t *testing.T) {
+	require.True(t, JobUnknown.CanTransitionTo(JobEnqueuing))
+	require.True(t, JobUnknown.CanTransitionTo(JobWaiting))
+	require.False(t, JobUnknown.CanTransitionTo(JobRunning))
+
+	require.True(t, JobWaiting.CanTransitionTo(JobEnqueuing))
+	require.True(t, JobWaiting.CanTransitionTo(JobDependencyFailed))
+	require.False(t, JobWaiting.CanTransitionTo(JobRunning))
+
+	require.True(t, JobEnqueuing.CanTransitionTo(JobRunning))
+	require.True(t, JobEnqueuing.CanTransitionTo(JobStopped))
+	require.False(t, JobEnqueuing.CanTransitionTo(JobSucceeded))
+
+	require.True(t, JobRunning.CanTransitionTo(JobRunning))
+	require.True(t, JobRunning.CanTransitionTo(JobSucceeded))
+	require.True(t, JobRunning.CanTransitionTo(JobWorkerOOM))
+	require.True(t, JobRunning.CanTransitionTo(JobPaused))
+	require.False(t, JobRunning.CanTransitionTo(JobEnqueuing))
+
+	require.True(t, JobPaused.CanTransitionTo(JobRunning))
+	require.True(t, JobPaused.CanTransitionTo(JobStopped))
+	require.False(t, JobPaused.CanTransitionTo(JobSucceeded))
+	require.False(t, JobEnqueuing.CanTransitionTo(JobPaused))
+
+	require.True(t, JobStopped.CanTransitionTo(JobStopped))
+	require.False(t, JobStopped.CanTransitionTo(JobRunning))
+	require.False(t, JobSucceeded.CanTransitionTo(JobStopped))
+	require.False(t, JobEnqueueFailed.CanTransitionTo(JobUnexpectedError))
+}
+
+// every in progress status must be able to move to a completed status
+func TestInProgressJobCodesCanComplete(t *testing.T) {
+	for i := range _jobCodes {
+		code := JobCode(i)
+		if !code.IsInProgress() {
+			continue
+		}
+		require.True(t, code.CanTransitionTo(JobStopped), code.String())
+		require.True(t, code.CanTransitionTo(JobUnexpectedError), code.String())
+	}
+}
@@ -30,4 +30,20 @@ type JobStatus struct {
 	BatchesInQueue int                   `json:"batches_in_queue"`
 	BatchMetrics   *metrics.BatchMetrics `json:"batch_metrics"`
 	WorkerCounts   *WorkerCounts         `json:"worker_counts"`
+	Progress       *JobProgress          `json:"progress"`
+	Output         *JobOutputStatus      `json:"output"`
+}
+
+// JobProgress is only available while the job is running; the throughput is measured over a sliding window, and is not available until the job has been running for long enough
+type JobProgress struct {
+	PercentComplete           float64  `json:"percent_complete"`
+	BatchesPerSecond          *float64 `json:"batches_per_second"`
+	BatchesPerSecondPerWorker *float64 `json:"batches_per_second_per_worker"`
+	EstimatedTimeRemaining    *float64 `json:"estimated_time_remaining"` // in seconds
+}
+
+type JobOutputStatus struct {
+	S3Path       string `json:"s3_path"` // where the job's results are written
+	Format       string `json:"format"`
+	ItemsWritten int    `json:"items_written"`
 }
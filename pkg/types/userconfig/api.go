@@ -17,11 +17,13 @@ limitations under the License.
 package userconfig
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types"
@@ -80,9 +82,10 @@ type ServerSideBatching struct {
 }
 
 type Networking struct {
-	Endpoint   *string        `json:"endpoint" yaml:"endpoint"`
-	LocalPort  *int           `json:"local_port" yaml:"local_port"`
-	APIGateway APIGatewayType `json:"api_gateway" yaml:"api_gateway"`
+	Endpoint     *string          `json:"endpoint" yaml:"endpoint"`
+	LocalPort    *int             `json:"local_port" yaml:"local_port"`
+	APIGateway   APIGatewayType   `json:"api_gateway" yaml:"api_gateway"`
+	EndpointAuth EndpointAuthType `json:"endpoint_auth" yaml:"endpoint_auth"`
 }
 
 type Compute struct {
@@ -93,18 +96,34 @@ type Compute struct {
 }
 
 type Autoscaling struct {
-	MinReplicas                  int32         `json:"min_replicas" yaml:"min_replicas"`
-	MaxReplicas                  int32         `json:"max_replicas" yaml:"max_replicas"`
-	InitReplicas                 int32         `json:"init_replicas" yaml:"init_replicas"`
-	TargetReplicaConcurrency     *float64      `json:"target_replica_concurrency" yaml:"target_replica_concurrency"`
-	MaxReplicaConcurrency        int64         `json:"max_replica_concurrency" yaml:"max_replica_concurrency"`
-	Window                       time.Duration `json:"window" yaml:"window"`
-	DownscaleStabilizationPeriod time.Duration `json:"downscale_stabilization_period" yaml:"downscale_stabilization_period"`
-	UpscaleStabilizationPeriod   time.Duration `json:"upscale_stabilization_period" yaml:"upscale_stabilization_period"`
-	MaxDownscaleFactor           float64       `json:"max_downscale_factor" yaml:"max_downscale_factor"`
-	MaxUpscaleFactor             float64       `json:"max_upscale_factor" yaml:"max_upscale_factor"`
-	DownscaleTolerance           float64       `json:"downscale_tolerance" yaml:"downscale_tolerance"`
-	UpscaleTolerance             float64       `json:"upscale_tolerance" yaml:"upscale_tolerance"`
+	MinReplicas                  int32                  `json:"min_replicas" yaml:"min_replicas"`
+	MaxReplicas                  int32                  `json:"max_replicas" yaml:"max_replicas"`
+	InitReplicas                 int32                  `json:"init_replicas" yaml:"init_replicas"`
+	TargetReplicaConcurrency     *float64               `json:"target_replica_concurrency" yaml:"target_replica_concurrency"`
+	TargetP95Latency             *time.Duration         `json:"target_p95_latency" yaml:"target_p95_latency"`
+	TargetRequestsPerSecond      *float64               `json:"target_requests_per_second" yaml:"target_requests_per_second"`
+	TargetGPUUtilization         *float64               `json:"target_gpu_utilization" yaml:"target_gpu_utilization"`
+	MaxReplicaConcurrency        int64                  `json:"max_replica_concurrency" yaml:"max_replica_concurrency"`
+	Window                       time.Duration          `json:"window" yaml:"window"`
+	DownscaleStabilizationPeriod time.Duration          `json:"downscale_stabilization_period" yaml:"downscale_stabilization_period"`
+	UpscaleStabilizationPeriod   time.Duration          `json:"upscale_stabilization_period" yaml:"upscale_stabilization_period"`
+	MaxDownscaleFactor           float64                `json:"max_downscale_factor" yaml:"max_downscale_factor"`
+	MaxUpscaleFactor             float64                `json:"max_upscale_factor" yaml:"max_upscale_factor"`
+	DownscaleTolerance           float64                `json:"downscale_tolerance" yaml:"downscale_tolerance"`
+	UpscaleTolerance             float64                `json:"upscale_tolerance" yaml:"upscale_tolerance"`
+	ScaleToZeroPeriod            time.Duration          `json:"scale_to_zero_period" yaml:"scale_to_zero_period"`
+	PredictiveScaling            bool                   `json:"predictive_scaling" yaml:"predictive_scaling"`
+	PredictiveScalingLeadTime    time.Duration          `json:"predictive_scaling_lead_time" yaml:"predictive_scaling_lead_time"`
+	Schedules                    []*AutoscalingSchedule `json:"schedules" yaml:"schedules"`
+}
+
+type AutoscalingSchedule struct {
+	Schedule    string        `json:"schedule" yaml:"schedule"`
+	Duration    time.Duration `json:"duration" yaml:"duration"`
+	MinReplicas *int32        `json:"min_replicas" yaml:"min_replicas"`
+	MaxReplicas *int32        `json:"max_replicas" yaml:"max_replicas"`
+
+	cronSchedule *cron.Schedule // set by ParseSchedule
 }
 
 type UpdateStrategy struct {
@@ -198,12 +217,24 @@ func (api *API) ToK8sAnnotations() map[string]string {
 	if api.Networking != nil {
 		annotations[EndpointAnnotationKey] = *api.Networking.Endpoint
 		annotations[APIGatewayAnnotationKey] = api.Networking.APIGateway.String()
+		if api.Networking.EndpointAuth != UnknownEndpointAuthType {
+			annotations[EndpointAuthAnnotationKey] = api.Networking.EndpointAuth.String()
+		}
 	}
 
 	if api.Autoscaling != nil {
 		annotations[MinReplicasAnnotationKey] = s.Int32(api.Autoscaling.MinReplicas)
 		annotations[MaxReplicasAnnotationKey] = s.Int32(api.Autoscaling.MaxReplicas)
 		annotations[TargetReplicaConcurrencyAnnotationKey] = s.Float64(*api.Autoscaling.TargetReplicaConcurrency)
+		if api.Autoscaling.TargetP95Latency != nil {
+			annotations[TargetP95LatencyAnnotationKey] = api.Autoscaling.TargetP95Latency.String()
+		}
+		if api.Autoscaling.TargetRequestsPerSecond != nil {
+			annotations[TargetRequestsPerSecondAnnotationKey] = s.Float64(*api.Autoscaling.TargetRequestsPerSecond)
+		}
+		if api.Autoscaling.TargetGPUUtilization != nil {
+			annotations[TargetGPUUtilizationAnnotationKey] = s.Float64(*api.Autoscaling.TargetGPUUtilization)
+		}
 		annotations[MaxReplicaConcurrencyAnnotationKey] = s.Int64(api.Autoscaling.MaxReplicaConcurrency)
 		annotations[WindowAnnotationKey] = api.Autoscaling.Window.String()
 		annotations[DownscaleStabilizationPeriodAnnotationKey] = api.Autoscaling.DownscaleStabilizationPeriod.String()
@@ -212,6 +243,13 @@ func (api *API) ToK8sAnnotations() map[string]string {
 		annotations[MaxUpscaleFactorAnnotationKey] = s.Float64(api.Autoscaling.MaxUpscaleFactor)
 		annotations[DownscaleToleranceAnnotationKey] = s.Float64(api.Autoscaling.DownscaleTolerance)
 		annotations[UpscaleToleranceAnnotationKey] = s.Float64(api.Autoscaling.UpscaleTolerance)
+		annotations[ScaleToZeroPeriodAnnotationKey] = api.Autoscaling.ScaleToZeroPeriod.String()
+		annotations[PredictiveScalingAnnotationKey] = s.Bool(api.Autoscaling.PredictiveScaling)
+		annotations[PredictiveScalingLeadTimeAnnotationKey] = api.Autoscaling.PredictiveScalingLeadTime.String()
+		if len(api.Autoscaling.Schedules) > 0 {
+			schedulesBytes, _ := json.Marshal(api.Autoscaling.Schedules)
+			annotations[SchedulesAnnotationKey] = string(schedulesBytes)
+		}
 	}
 	return annotations
 }
@@ -224,6 +262,20 @@ func APIGatewayFromAnnotations(k8sObj kmeta.Object) (APIGatewayType, error) {
 	return apiGatewayType, nil
 }
 
+// APIs which were deployed before endpoint_auth was introduced don't have the annotation, and don't require authentication
+func EndpointAuthFromAnnotations(k8sObj kmeta.Object) (EndpointAuthType, error) {
+	endpointAuthStr, ok := k8sObj.GetAnnotations()[EndpointAuthAnnotationKey]
+	if !ok {
+		return NoneEndpointAuthType, nil
+	}
+
+	endpointAuthType := EndpointAuthTypeFromString(endpointAuthStr)
+	if endpointAuthType == UnknownEndpointAuthType {
+		return UnknownEndpointAuthType, ErrorUnknownEndpointAuthType()
+	}
+	return endpointAuthType, nil
+}
+
 func AutoscalingFromAnnotations(k8sObj kmeta.Object) (*Autoscaling, error) {
 	a := Autoscaling{}
 
@@ -245,6 +297,30 @@ func AutoscalingFromAnnotations(k8sObj kmeta.Object) (*Autoscaling, error) {
 	}
 	a.TargetReplicaConcurrency = &targetReplicaConcurrency
 
+	if _, ok := k8sObj.GetAnnotations()[TargetP95LatencyAnnotationKey]; ok {
+		targetP95Latency, err := k8s.ParseDurationAnnotation(k8sObj, TargetP95LatencyAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetP95Latency = &targetP95Latency
+	}
+
+	if _, ok := k8sObj.GetAnnotations()[TargetRequestsPerSecondAnnotationKey]; ok {
+		targetRequestsPerSecond, err := k8s.ParseFloat64Annotation(k8sObj, TargetRequestsPerSecondAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetRequestsPerSecond = &targetRequestsPerSecond
+	}
+
+	if _, ok := k8sObj.GetAnnotations()[TargetGPUUtilizationAnnotationKey]; ok {
+		targetGPUUtilization, err := k8s.ParseFloat64Annotation(k8sObj, TargetGPUUtilizationAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetGPUUtilization = &targetGPUUtilization
+	}
+
 	maxReplicaConcurrency, err := k8s.ParseInt64Annotation(k8sObj, MaxReplicaConcurrencyAnnotationKey)
 	if err != nil {
 		return nil, err
@@ -293,6 +369,35 @@ func AutoscalingFromAnnotations(k8sObj kmeta.Object) (*Autoscaling, error) {
 	}
 	a.UpscaleTolerance = upscaleTolerance
 
+	scaleToZeroPeriod, err := k8s.ParseDurationAnnotation(k8sObj, ScaleToZeroPeriodAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+	a.ScaleToZeroPeriod = scaleToZeroPeriod
+
+	predictiveScaling, err := k8s.ParseBoolAnnotation(k8sObj, PredictiveScalingAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+	a.PredictiveScaling = predictiveScaling
+
+	predictiveScalingLeadTime, err := k8s.ParseDurationAnnotation(k8sObj, PredictiveScalingLeadTimeAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+	a.PredictiveScalingLeadTime = predictiveScalingLeadTime
+
+	if schedulesStr, ok := k8sObj.GetAnnotations()[SchedulesAnnotationKey]; ok {
+		if err := json.Unmarshal([]byte(schedulesStr), &a.Schedules); err != nil {
+			return nil, k8s.ErrorParseAnnotation(SchedulesAnnotationKey, schedulesStr, "list of schedules")
+		}
+		for _, schedule := range a.Schedules {
+			if err := schedule.ParseSchedule(); err != nil {
+				return nil, k8s.ErrorParseAnnotation(SchedulesAnnotationKey, schedulesStr, "list of schedules")
+			}
+		}
+	}
+
 	return &a, nil
 }
 
@@ -433,6 +538,9 @@ func (networking *Networking) UserStr(provider types.ProviderType) string {
 	if provider == types.AWSProviderType {
 		sb.WriteString(fmt.Sprintf("%s: %s\n", APIGatewayKey, networking.APIGateway))
 	}
+	if provider == types.AWSProviderType && networking.EndpointAuth != UnknownEndpointAuthType {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", EndpointAuthKey, networking.EndpointAuth))
+	}
 	return sb.String()
 }
 
@@ -513,6 +621,15 @@ func (autoscaling *Autoscaling) UserStr() string {
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxReplicasKey, s.Int32(autoscaling.MaxReplicas)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", InitReplicasKey, s.Int32(autoscaling.InitReplicas)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", TargetReplicaConcurrencyKey, s.Float64(*autoscaling.TargetReplicaConcurrency)))
+	if autoscaling.TargetP95Latency != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetP95LatencyKey, autoscaling.TargetP95Latency.String()))
+	}
+	if autoscaling.TargetRequestsPerSecond != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetRequestsPerSecondKey, s.Float64(*autoscaling.TargetRequestsPerSecond)))
+	}
+	if autoscaling.TargetGPUUtilization != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetGPUUtilizationKey, s.Float64(*autoscaling.TargetGPUUtilization)))
+	}
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxReplicaConcurrencyKey, s.Int64(autoscaling.MaxReplicaConcurrency)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", WindowKey, autoscaling.Window.String()))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", DownscaleStabilizationPeriodKey, autoscaling.DownscaleStabilizationPeriod.String()))
@@ -521,9 +638,87 @@ func (autoscaling *Autoscaling) UserStr() string {
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxUpscaleFactorKey, s.Float64(autoscaling.MaxUpscaleFactor)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", DownscaleToleranceKey, s.Float64(autoscaling.DownscaleTolerance)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", UpscaleToleranceKey, s.Float64(autoscaling.UpscaleTolerance)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", ScaleToZeroPeriodKey, autoscaling.ScaleToZeroPeriod.String()))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PredictiveScalingKey, s.Bool(autoscaling.PredictiveScaling)))
+	if autoscaling.PredictiveScaling {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", PredictiveScalingLeadTimeKey, autoscaling.PredictiveScalingLeadTime.String()))
+	}
+	if len(autoscaling.Schedules) > 0 {
+		sb.WriteString(fmt.Sprintf("%s:\n", SchedulesKey))
+		for _, schedule := range autoscaling.Schedules {
+			sb.WriteString(s.Indent(schedule.UserStr(), "  "))
+		}
+	}
 	return sb.String()
 }
 
+func (schedule *AutoscalingSchedule) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- %s: %s\n", ScheduleKey, schedule.Schedule))
+	sb.WriteString(fmt.Sprintf(s.Indent("%s: %s\n", "  "), DurationKey, schedule.Duration.String()))
+	if schedule.MinReplicas != nil {
+		sb.WriteString(fmt.Sprintf(s.Indent("%s: %s\n", "  "), MinReplicasKey, s.Int32(*schedule.MinReplicas)))
+	}
+	if schedule.MaxReplicas != nil {
+		sb.WriteString(fmt.Sprintf(s.Indent("%s: %s\n", "  "), MaxReplicasKey, s.Int32(*schedule.MaxReplicas)))
+	}
+	return sb.String()
+}
+
+// ParseSchedule parses the schedule's cron expression, so that it isn't re-parsed each time that the schedule is evaluated
+func (schedule *AutoscalingSchedule) ParseSchedule() error {
+	cronSchedule, err := cron.ParseSchedule(schedule.Schedule)
+	if err != nil {
+		return err
+	}
+	schedule.cronSchedule = cronSchedule
+	return nil
+}
+
+// IsActive returns whether the schedule's cron expression (evaluated in UTC) fired within its duration before t
+func (schedule *AutoscalingSchedule) IsActive(t time.Time) bool {
+	cronSchedule := schedule.cronSchedule
+	if cronSchedule == nil {
+		// e.g. the schedule was read from a spec file rather than validated
+		var err error
+		cronSchedule, err = cron.ParseSchedule(schedule.Schedule)
+		if err != nil {
+			return false
+		}
+	}
+
+	t = t.UTC()
+	lastStart := cronSchedule.Last(t, t.Add(-schedule.Duration))
+	return lastStart != nil && t.Sub(*lastStart) < schedule.Duration
+}
+
+// ActiveSchedule returns the first of the autoscaling schedules which is active at t, or nil if none are active
+func (autoscaling *Autoscaling) ActiveSchedule(t time.Time) *AutoscalingSchedule {
+	for _, schedule := range autoscaling.Schedules {
+		if schedule.IsActive(t) {
+			return schedule
+		}
+	}
+	return nil
+}
+
+// ReplicaBoundsAt returns the min and max replicas at t, taking the active autoscaling schedule (if any) into account
+func (autoscaling *Autoscaling) ReplicaBoundsAt(t time.Time) (int32, int32) {
+	minReplicas := autoscaling.MinReplicas
+	maxReplicas := autoscaling.MaxReplicas
+
+	if schedule := autoscaling.ActiveSchedule(t); schedule != nil {
+		if schedule.MinReplicas != nil {
+			minReplicas = *schedule.MinReplicas
+		}
+		if schedule.MaxReplicas != nil {
+			maxReplicas = *schedule.MaxReplicas
+		}
+	}
+
+	return minReplicas, maxReplicas
+}
+
 func (updateStrategy *UpdateStrategy) UserStr() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxSurgeKey, updateStrategy.MaxSurge))
@@ -58,9 +58,10 @@ const (
 	ModelTypeKey = "model_type"
 
 	// Networking
-	APIGatewayKey = "api_gateway"
-	EndpointKey   = "endpoint"
-	LocalPortKey  = "local_port"
+	APIGatewayKey   = "api_gateway"
+	EndpointKey     = "endpoint"
+	LocalPortKey    = "local_port"
+	EndpointAuthKey = "endpoint_auth"
 
 	// Compute
 	CPUKey = "cpu"
@@ -73,6 +74,9 @@ const (
 	MaxReplicasKey                  = "max_replicas"
 	InitReplicasKey                 = "init_replicas"
 	TargetReplicaConcurrencyKey     = "target_replica_concurrency"
+	TargetP95LatencyKey             = "target_p95_latency"
+	TargetRequestsPerSecondKey      = "target_requests_per_second"
+	TargetGPUUtilizationKey         = "target_gpu_utilization"
 	MaxReplicaConcurrencyKey        = "max_replica_concurrency"
 	WindowKey                       = "window"
 	DownscaleStabilizationPeriodKey = "downscale_stabilization_period"
@@ -81,6 +85,12 @@ const (
 	MaxUpscaleFactorKey             = "max_upscale_factor"
 	DownscaleToleranceKey           = "downscale_tolerance"
 	UpscaleToleranceKey             = "upscale_tolerance"
+	ScaleToZeroPeriodKey            = "scale_to_zero_period"
+	PredictiveScalingKey            = "predictive_scaling"
+	PredictiveScalingLeadTimeKey    = "predictive_scaling_lead_time"
+	SchedulesKey                    = "schedules"
+	ScheduleKey                     = "schedule"
+	DurationKey                     = "duration"
 
 	// UpdateStrategy
 	MaxSurgeKey       = "max_surge"
@@ -89,11 +99,15 @@ const (
 	// K8s annotation
 	EndpointAnnotationKey                     = "networking.cortex.dev/endpoint"
 	APIGatewayAnnotationKey                   = "networking.cortex.dev/api-gateway"
+	EndpointAuthAnnotationKey                 = "networking.cortex.dev/endpoint-auth"
 	ProcessesPerReplicaAnnotationKey          = "predictor.cortex.dev/processes-per-replica"
 	ThreadsPerProcessAnnotationKey            = "predictor.cortex.dev/threads-per-process"
 	MinReplicasAnnotationKey                  = "autoscaling.cortex.dev/min-replicas"
 	MaxReplicasAnnotationKey                  = "autoscaling.cortex.dev/max-replicas"
 	TargetReplicaConcurrencyAnnotationKey     = "autoscaling.cortex.dev/target-replica-concurrency"
+	TargetP95LatencyAnnotationKey             = "autoscaling.cortex.dev/target-p95-latency"
+	TargetRequestsPerSecondAnnotationKey      = "autoscaling.cortex.dev/target-requests-per-second"
+	TargetGPUUtilizationAnnotationKey         = "autoscaling.cortex.dev/target-gpu-utilization"
 	MaxReplicaConcurrencyAnnotationKey        = "autoscaling.cortex.dev/max-replica-concurrency"
 	WindowAnnotationKey                       = "autoscaling.cortex.dev/window"
 	DownscaleStabilizationPeriodAnnotationKey = "autoscaling.cortex.dev/downscale-stabilization-period"
@@ -102,4 +116,8 @@ const (
 	MaxUpscaleFactorAnnotationKey             = "autoscaling.cortex.dev/max-upscale-factor"
 	DownscaleToleranceAnnotationKey           = "autoscaling.cortex.dev/downscale-tolerance"
 	UpscaleToleranceAnnotationKey             = "autoscaling.cortex.dev/upscale-tolerance"
+	ScaleToZeroPeriodAnnotationKey            = "autoscaling.cortex.dev/scale-to-zero-period"
+	PredictiveScalingAnnotationKey            = "autoscaling.cortex.dev/predictive-scaling"
+	PredictiveScalingLeadTimeAnnotationKey    = "autoscaling.cortex.dev/predictive-scaling-lead-time"
+	SchedulesAnnotationKey                    = "autoscaling.cortex.dev/schedules"
 )
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+type EndpointAuthType int
+
+const (
+	UnknownEndpointAuthType EndpointAuthType = iota
+	NoneEndpointAuthType
+	TokenEndpointAuthType
+)
+
+var _endpointAuthTypes = []string{
+	"unknown",
+	"none",
+	"token",
+}
+
+func EndpointAuthTypeFromString(s string) EndpointAuthType {
+	for i := 0; i < len(_endpointAuthTypes); i++ {
+		if s == _endpointAuthTypes[i] {
+			return EndpointAuthType(i)
+		}
+	}
+	return UnknownEndpointAuthType
+}
+
+func EndpointAuthTypeStrings() []string {
+	return _endpointAuthTypes[1:]
+}
+
+func (t EndpointAuthType) String() string {
+	return _endpointAuthTypes[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t EndpointAuthType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *EndpointAuthType) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_endpointAuthTypes); i++ {
+		if enum == _endpointAuthTypes[i] {
+			*t = EndpointAuthType(i)
+			return nil
+		}
+	}
+
+	*t = UnknownEndpointAuthType
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *EndpointAuthType) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t EndpointAuthType) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -21,7 +21,8 @@ import (
 )
 
 const (
-	ErrUnknownAPIGatewayType = "userconfig.unknown_api_gateway_type"
+	ErrUnknownAPIGatewayType   = "userconfig.unknown_api_gateway_type"
+	ErrUnknownEndpointAuthType = "userconfig.unknown_endpoint_auth_type"
 )
 
 func ErrorUnknownAPIGatewayType() error {
@@ -30,3 +31,10 @@ func ErrorUnknownAPIGatewayType() error {
 		Message: "unknown api gateway type",
 	})
 }
+
+func ErrorUnknownEndpointAuthType() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnknownEndpointAuthType,
+		Message: "unknown endpoint auth type",
+	})
+}
@@ -0,0 +1,87 @@
+# Copyright 2020 Cortex Labs, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import socket
+import threading
+from concurrent.futures import ThreadPoolExecutor
+
+from cortex.lib.log import cx_logger
+
+
+class RequestMonitorClient:
+    """
+    Reports the start and end of each request to the request monitor over a unix socket, so that
+    it can track the exact number of in-flight requests.
+
+    Each process has its own connection; if the connection is lost (e.g. the request monitor
+    restarted), it is re-established on the next event.
+
+    Events are sent from a single background thread, so that reporting them never blocks the
+    event loop, and so that they are sent in the order in which they occurred.
+    """
+
+    def __init__(self, socket_path, timeout=0.1):
+        self.socket_path = socket_path
+        self.timeout = timeout  # seconds
+        self._sock = None
+        self._lock = threading.Lock()
+        self._is_failing = False
+        self._executor = ThreadPoolExecutor(max_workers=1)
+
+    def request_started(self):
+        self._executor.submit(self._send, b"start\n")
+
+    def request_ended(self):
+        self._executor.submit(self._send, b"end\n")
+
+    def _send(self, event):
+        with self._lock:
+            # retry once, since an existing connection may have been closed by the request monitor
+            for _ in range(2):
+                try:
+                    if self._sock is None:
+                        self._connect()
+                    self._sock.sendall(event)
+                    if self._is_failing:
+                        cx_logger().info("reconnected to the request monitor")
+                        self._is_failing = False
+                    return
+                except OSError as e:
+                    self._close()
+                    error = e
+
+            # the request isn't counted, but this shouldn't fail the request
+            if not self._is_failing:
+                cx_logger().warn(
+                    f"unable to reach the request monitor at {self.socket_path}: {error}"
+                )
+                self._is_failing = True
+
+    def _connect(self):
+        sock = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+        sock.settimeout(self.timeout)
+        try:
+            sock.connect(self.socket_path)
+        except:
+            sock.close()
+            raise
+        self._sock = sock
+
+    def _close(self):
+        if self._sock is not None:
+            try:
+                self._sock.close()
+            except OSError:
+                pass
+            self._sock = None
@@ -15,6 +15,7 @@
 import os
 import base64
 import time
+import subprocess
 from pathlib import Path
 import json
 
@@ -96,6 +97,24 @@ class API:
             ]
             self.post_metrics(metrics)
 
+    def post_gpu_utilization_metrics(self):
+        try:
+            output = subprocess.check_output(
+                ["nvidia-smi", "--query-gpu=utilization.gpu", "--format=csv,noheader,nounits"]
+            )
+            utilizations = [float(line) for line in output.decode().strip().splitlines()]
+        except:
+            cx_logger().warn("failure encountered while reading gpu utilization", exc_info=True)
+            return
+
+        if len(utilizations) > 0:
+            metrics = [
+                self.gpu_utilization_metric(
+                    self.metric_dimensions(), sum(utilizations) / len(utilizations)
+                )
+            ]
+            self.post_metrics(metrics)
+
     def post_metrics(self, metrics):
         try:
             if self.statsd is None:
@@ -147,6 +166,13 @@ class API:
             "Value": total_time,  # milliseconds
         }
 
+    def gpu_utilization_metric(self, dimensions, utilization):
+        return {
+            "MetricName": "GPUUtilization",
+            "Dimensions": dimensions,
+            "Value": utilization,  # percent
+        }
+
     def prediction_metrics(self, dimensions, prediction_value):
         if self.monitoring.model_type == "classification":
             dimensions_with_class = dimensions + [{"Name": "Class", "Value": str(prediction_value)}]
@@ -20,6 +20,9 @@ import time
 import json
 import threading
 import math
+import signal
+import uuid
+from contextlib import contextmanager
 
 import boto3
 import botocore
@@ -32,7 +35,13 @@ from cortex.lib.storage import S3, LocalStorage, FileLock
 from cortex.lib.exceptions import UserRuntimeException
 
 API_LIVENESS_UPDATE_PERIOD = 5  # seconds
-MAXIMUM_MESSAGE_VISIBILITY = 60 * 60 * 12  # 12 hours is the maximum message visibility
+
+# a worker which has been terminated must not exit successfully, since kubernetes would consider the
+# job to be complete; the operator excludes terminated workers from the job's failed workers
+TERMINATED_EXIT_CODE = 128 + signal.SIGTERM
+
+# set when the worker receives SIGTERM (when its job is paused or its number of workers is decreased)
+is_terminating = threading.Event()
 
 local_cache = {
     "api_spec": None,
@@ -43,6 +52,9 @@ local_cache = {
     "client": None,
     "class_set": set(),
     "sqs_client": None,
+    "storage": None,
+    "output_storage": None,
+    "output_prefix": None,
 }
 
 
@@ -65,6 +77,38 @@ def time_per_batch_metric(total_time_seconds):
     return {"MetricName": "TimePerBatch", "Dimensions": dimensions(), "Value": total_time_seconds}
 
 
+def output_items_metric(num_items):
+    return {
+        "MetricName": "OutputItems", "Dimensions": dimensions(), "Unit": "Count", "Value": num_items
+    }
+
+
+def write_output(response, batch_id):
+    """
+    Writes the predictor's response for a batch to the job's output (if the job has one), and
+    returns the number of items that were written. Each batch is written to its own file, named
+    after the batch id, so a retried batch overwrites its previous output.
+    """
+
+    output = local_cache["job_spec"].get("output")
+    if output is None or response is None:
+        return 0
+
+    storage = local_cache["output_storage"]
+    is_list = isinstance(response, (list, tuple))
+
+    if output["format"] == "json_lines":
+        items = response if is_list else [response]
+        body = "".join(json.dumps(item) + "\n" for item in items)
+        storage.put_str(body, os.path.join(local_cache["output_prefix"], batch_id + ".jsonl"))
+        return len(items)
+
+    storage.put_str(
+        json.dumps(response), os.path.join(local_cache["output_prefix"], batch_id + ".json")
+    )
+    return len(response) if is_list else 1
+
+
 def build_predict_args(payload, batch_id):
     args = {}
 
@@ -79,6 +123,19 @@ def build_predict_args(payload, batch_id):
     return args
 
 
+def get_batch_payload(message):
+    """
+    Batches which exceed the SQS message size limit are stored in the cluster's bucket by the
+    operator, in which case the message contains the batch's key in the spilled_batch attribute.
+    """
+
+    spilled_batch = message.get("MessageAttributes", {}).get("spilled_batch")
+    if spilled_batch is not None:
+        return local_cache["storage"].get_json(spilled_batch["StringValue"])
+
+    return json.loads(message["Body"])
+
+
 def get_job_spec(storage, cache_dir, job_spec_path):
     local_spec_path = os.path.join(cache_dir, "job_spec.json")
     _, key = S3.deconstruct_s3_path(job_spec_path)
@@ -100,6 +157,138 @@ def get_total_messages_in_queue():
     return visible_count, not_visible_count
 
 
+def renew_visibility(receipt_handle, stop_event):
+    sqs_client = local_cache["sqs_client"]
+    queue_url = local_cache["job_spec"]["sqs_url"]
+    timeout = local_cache["job_spec"]["timeout"]
+
+    while not stop_event.wait(timeout / 2):
+        try:
+            sqs_client.change_message_visibility(
+                QueueUrl=queue_url, ReceiptHandle=receipt_handle, VisibilityTimeout=timeout
+            )
+        except Exception:
+            # the message may be received by another worker if the visibility timeout expires
+            cx_logger().exception("failed to extend the visibility timeout of the message")
+
+
+@contextmanager
+def renewing_visibility(receipt_handle):
+    """
+    Keeps the message hidden from other workers while it is being processed, by extending its
+    visibility timeout before it expires.
+    """
+
+    stop_event = threading.Event()
+    renewer = threading.Thread(
+        target=renew_visibility, args=(receipt_handle, stop_event), daemon=True
+    )
+    renewer.start()
+    try:
+        yield
+    finally:
+        # stop renewing before the caller deletes or releases the message
+        stop_event.set()
+        renewer.join()
+
+
+def release_message(receipt_handle):
+    sqs_client = local_cache["sqs_client"]
+    queue_url = local_cache["job_spec"]["sqs_url"]
+    sqs_client.change_message_visibility(
+        QueueUrl=queue_url, ReceiptHandle=receipt_handle, VisibilityTimeout=0
+    )
+
+
+def release_job_complete_message(receipt_handle):
+    job_spec = local_cache["job_spec"]
+    sqs_client = local_cache["sqs_client"]
+    queue_url = job_spec["sqs_url"]
+
+    if job_spec.get("max_retries") is None:
+        release_message(receipt_handle)
+        return
+
+    # with a redrive policy, each release counts as a receive, which would eventually move the
+    # job_complete message to the dead letter queue, so it is replaced instead
+    message_id = str(uuid.uuid4())
+    sqs_client.send_message(
+        QueueUrl=queue_url,
+        MessageBody='"job_complete"',
+        MessageDeduplicationId=message_id,
+        MessageGroupId=message_id,
+        MessageAttributes={"job_complete": {"DataType": "String", "StringValue": "true"}},
+    )
+    sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
+
+
+def handle_sigterm(signum, frame):
+    # the worker finishes the batch that it is processing, so that the batch isn't received again
+    # (which would count towards its retries) and its metrics are posted
+    is_terminating.set()
+
+
+def exit_if_terminating():
+    if is_terminating.is_set():
+        cx_logger().info("the worker has been terminated, exiting...")
+        sys.exit(TERMINATED_EXIT_CODE)
+
+
+def handle_failed_batch(message):
+    """
+    Returns whether the batch will be retried. If the job was submitted with max_retries, the batch
+    is released so that it can be received again, and once it has failed max_retries + 1 times, SQS
+    moves it to the job's dead letter queue on the next receive.
+    """
+
+    job_spec = local_cache["job_spec"]
+    sqs_client = local_cache["sqs_client"]
+    queue_url = job_spec["sqs_url"]
+    receipt_handle = message["ReceiptHandle"]
+
+    max_retries = job_spec.get("max_retries")
+    if max_retries is None:
+        sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
+        return False
+
+    release_message(receipt_handle)
+
+    receive_count = int(message.get("Attributes", {}).get("ApproximateReceiveCount", 1))
+    return receive_count <= max_retries
+
+
+def wait_for_remaining_batches():
+    """
+    Returns True once the job_complete message is the only message left in the queue, or False if
+    there are other messages which are visible.
+    """
+
+    should_run_on_job_complete = False
+
+    while True:
+        # the job_complete message is released so that the remaining workers can receive it
+        if is_terminating.is_set():
+            return False
+
+        visible_count, not_visible_count = get_total_messages_in_queue()
+
+        # if there are other messages that are visible, release this message and get the other ones (should rarely happen for FIFO)
+        if visible_count > 0:
+            return False
+
+        if should_run_on_job_complete:
+            # double check that the queue is still empty (except for the job_complete message)
+            if not_visible_count <= 1:
+                return True
+            else:
+                should_run_on_job_complete = False
+
+        if not_visible_count <= 1:
+            should_run_on_job_complete = True
+
+        time.sleep(20)
+
+
 def handle_on_complete(message):
     job_spec = local_cache["job_spec"]
     predictor_impl = local_cache["predictor_impl"]
@@ -112,32 +301,19 @@ def handle_on_complete(message):
             sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
             return True
 
-        should_run_on_job_complete = False
+        # the message must stay hidden from other workers while waiting and running on_job_complete
+        with renewing_visibility(receipt_handle):
+            is_job_complete = wait_for_remaining_batches()
+            if is_job_complete:
+                cx_logger().info("executing on_job_complete")
+                predictor_impl.on_job_complete()
 
-        while True:
-            visible_count, not_visible_count = get_total_messages_in_queue()
+        if not is_job_complete:
+            release_job_complete_message(receipt_handle)
+            return False
 
-            # if there are other messages that are visible, release this message and get the other ones (should rarely happen for FIFO)
-            if visible_count > 0:
-                sqs_client.change_message_visibility(
-                    QueueUrl=queue_url, ReceiptHandle=receipt_handle, VisibilityTimeout=0
-                )
-                return False
-
-            if should_run_on_job_complete:
-                # double check that the queue is still empty (except for the job_complete message)
-                if not_visible_count <= 1:
-                    cx_logger().info("executing on_job_complete")
-                    predictor_impl.on_job_complete()
-                    sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
-                    return True
-                else:
-                    should_run_on_job_complete = False
-
-            if not_visible_count <= 1:
-                should_run_on_job_complete = True
-
-            time.sleep(20)
+        sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
+        return True
     except:
         sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
         raise
@@ -154,16 +330,20 @@ def sqs_loop():
     no_messages_found_in_previous_iteration = False
 
     while True:
+        exit_if_terminating()
+
         response = sqs_client.receive_message(
             QueueUrl=queue_url,
             MaxNumberOfMessages=1,
             WaitTimeSeconds=10,
-            VisibilityTimeout=MAXIMUM_MESSAGE_VISIBILITY,
+            VisibilityTimeout=job_spec["timeout"],
             MessageAttributeNames=["All"],
+            AttributeNames=["ApproximateReceiveCount"],
         )
 
         if response.get("Messages") is None or len(response["Messages"]) == 0:
             if no_messages_found_in_previous_iteration:
+                exit_if_terminating()
                 cx_logger().info("no batches left in queue, exiting...")
                 return
             else:
@@ -190,20 +370,26 @@ def sqs_loop():
 
             start_time = time.time()
 
-            payload = json.loads(message["Body"])
+            payload = get_batch_payload(message)
             batch_id = message["MessageId"]
-            predictor_impl.predict(**build_predict_args(payload, batch_id))
-
-            api_spec.post_metrics(
-                [success_counter_metric(), time_per_batch_metric(time.time() - start_time)]
-            )
+            with renewing_visibility(receipt_handle):
+                response = predictor_impl.predict(**build_predict_args(payload, batch_id))
+                num_output_items = write_output(response, batch_id)
+
+            metrics = [success_counter_metric(), time_per_batch_metric(time.time() - start_time)]
+            if num_output_items > 0:
+                metrics.append(output_items_metric(num_output_items))
+            api_spec.post_metrics(metrics)
+            sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
         except Exception:
-            api_spec.post_metrics(
-                [failed_counter_metric(), time_per_batch_metric(time.time() - start_time)]
-            )
             cx_logger().exception("failed to process batch")
-        finally:
-            sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
+            if handle_failed_batch(message):
+                # the batch only counts as failed once it has run out of retries
+                cx_logger().info(f"batch {message['MessageId']} will be retried")
+            else:
+                api_spec.post_metrics(
+                    [failed_counter_metric(), time_per_batch_metric(time.time() - start_time)]
+                )
 
 
 def start():
@@ -252,9 +438,18 @@ def start():
     local_cache["predictor_impl"] = predictor_impl
     local_cache["predict_fn_args"] = inspect.getfullargspec(predictor_impl.predict).args
     local_cache["sqs_client"] = boto3.client("sqs", region_name=os.environ["AWS_REGION"])
+    local_cache["storage"] = storage
+
+    if job_spec.get("output") is not None:
+        output_s3_path = util.ensure_suffix(job_spec["output"]["s3_path"], "/")
+        output_bucket, output_prefix = S3.deconstruct_s3_path(output_s3_path)
+        local_cache["output_storage"] = S3(bucket=output_bucket, region=os.environ["AWS_REGION"])
+        local_cache["output_prefix"] = os.path.join(output_prefix, job_spec["job_id"])
 
     open("/mnt/workspace/api_readiness.txt", "a").close()
 
+    signal.signal(signal.SIGTERM, handle_sigterm)
+
     cx_logger().info("polling for batches...")
     sqs_loop()
 
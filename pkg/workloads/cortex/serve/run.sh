@@ -86,4 +86,9 @@ if [ -f "/mnt/project/requirements.txt" ]; then
     pip --no-cache-dir install -r /mnt/project/requirements.txt
 fi
 
+# the batch worker is exec'd so that it receives SIGTERM when it is terminated (see batch.py)
+if [ "$CORTEX_KIND" == "BatchAPI" ]; then
+    exec /opt/conda/envs/env/bin/python /src/cortex/serve/start.py
+fi
+
 /opt/conda/envs/env/bin/python /src/cortex/serve/start.py
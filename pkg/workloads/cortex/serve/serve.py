@@ -37,12 +37,14 @@ from cortex.lib.type import API, get_spec
 from cortex.lib.log import cx_logger
 from cortex.lib.storage import S3, LocalStorage, FileLock
 from cortex.lib.exceptions import UserRuntimeException
+from cortex.lib.request_monitor import RequestMonitorClient
 
 API_SUMMARY_MESSAGE = (
     "make a prediction by sending a post request to this endpoint with a json payload"
 )
 
 API_LIVENESS_UPDATE_PERIOD = 5  # seconds
+GPU_UTILIZATION_UPDATE_PERIOD = 10  # seconds
 
 
 request_thread_pool = ThreadPoolExecutor(max_workers=int(os.environ["CORTEX_THREADS_PER_PROCESS"]))
@@ -66,6 +68,7 @@ local_cache = {
     "predict_route": None,
     "client": None,
     "class_set": set(),
+    "request_monitor": None,
 }
 
 
@@ -75,6 +78,11 @@ def update_api_liveness():
         f.write(str(math.ceil(time.time())))
 
 
+def update_gpu_utilization():
+    threading.Timer(GPU_UTILIZATION_UPDATE_PERIOD, update_gpu_utilization).start()
+    local_cache["api"].post_gpu_utilization_metrics()
+
+
 @app.on_event("startup")
 def startup():
     open("/mnt/workspace/api_readiness.txt", "a").close()
@@ -121,16 +129,23 @@ async def register_request(request: Request, call_next):
     request.state.start_time = time.time()
 
     file_id = None
+    request_monitor = None
     response = None
     try:
         if is_prediction_request(request):
-            if local_cache["provider"] != "local":
+            if local_cache["request_monitor"] is not None:
+                request_monitor = local_cache["request_monitor"]
+                request_monitor.request_started()
+            elif local_cache["provider"] != "local":
                 request_id = request.headers["x-request-id"]
                 file_id = f"/mnt/requests/{request_id}"
                 open(file_id, "a").close()
 
         response = await call_next(request)
     finally:
+        if request_monitor is not None:
+            request_monitor.request_ended()
+
         if file_id is not None:
             try:
                 os.remove(file_id)
@@ -327,6 +342,11 @@ def start_fn():
         if provider != "local":
             predict_route = "/predict"
         local_cache["predict_route"] = predict_route
+
+        # if the request monitor isn't listening on a socket, it counts the files in /mnt/requests instead
+        request_monitor_socket = os.getenv("CORTEX_REQUEST_MONITOR_SOCKET")
+        if provider != "local" and request_monitor_socket:
+            local_cache["request_monitor"] = RequestMonitorClient(request_monitor_socket)
     except:
         cx_logger().exception("failed to start api")
         sys.exit(1)
@@ -341,6 +361,15 @@ def start_fn():
         except:
             cx_logger().warn("an error occurred while attempting to load classes", exc_info=True)
 
+    # the operator's autoscaler reads the GPU utilization from cloudwatch
+    autoscaling = raw_api_spec.get("autoscaling")
+    if (
+        provider != "local"
+        and autoscaling is not None
+        and autoscaling.get("target_gpu_utilization") is not None
+    ):
+        update_gpu_utilization()
+
     app.add_api_route(local_cache["predict_route"], predict, methods=["POST"])
     app.add_api_route(local_cache["predict_route"], get_summary, methods=["GET"])
 